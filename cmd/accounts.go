@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const defaultAccountName = "default"
+
+type AccountConfig struct {
+	Name        string           `mapstructure:"name"`
+	TokenFile   string           `mapstructure:"token-file"`
+	OAuth       *OAuthConfig     `mapstructure:"oauth"`
+	Resume      string           `mapstructure:"resume"`
+	Resumes     []string         `mapstructure:"resumes"`
+	ExcludeFile string           `mapstructure:"exclude-file"`
+	RateLimit   *RateLimitConfig `mapstructure:"rate-limit"`
+}
+
+type RateLimitConfig struct {
+	// RequestsPerSecond limits requests to hh.ru API. Zero means no limit.
+	RequestsPerSecond float64 `mapstructure:"requests-per-second"`
+	// MaxApplications limits applications sent in a single run. Zero means no limit.
+	MaxApplications int `mapstructure:"max-applications"`
+	// DailyApplications limits applications in 24 hours when the quota is enabled. Zero means the hh.ru limit of 200.
+	DailyApplications int `mapstructure:"daily-applications"`
+}
+
+// accounts returns the configured accounts. Without the accounts section a single account
+// is built from the top-level settings. Missing resume and rate limit are taken from the top level too.
+func accounts(config *Config) ([]*AccountConfig, error) {
+	var (
+		resume  string
+		resumes []string
+	)
+	if config.Apply != nil {
+		resume, resumes = config.Apply.Resume, config.Apply.Resumes
+	}
+
+	if len(config.Accounts) == 0 {
+		tokenFile := strings.TrimSpace(config.TokenFile)
+		if tokenFile == "" {
+			tokenFile = strings.TrimSpace(viper.GetString("token-file"))
+		}
+
+		return []*AccountConfig{{
+			Name:        defaultAccountName,
+			TokenFile:   tokenFile,
+			OAuth:       config.OAuth,
+			Resume:      resume,
+			Resumes:     resumes,
+			ExcludeFile: config.ExcludeFile,
+			RateLimit:   config.RateLimit,
+		}}, nil
+	}
+
+	seen := make(map[string]bool, len(config.Accounts))
+	for idx, account := range config.Accounts {
+		if account == nil {
+			return nil, fmt.Errorf("account #%d is empty", idx)
+		}
+
+		account.Name = strings.TrimSpace(account.Name)
+		if account.Name == "" {
+			return nil, fmt.Errorf("account #%d has no name", idx)
+		}
+
+		if seen[account.Name] {
+			return nil, fmt.Errorf("account %s is configured twice", account.Name)
+		}
+		seen[account.Name] = true
+
+		if account.Resume == "" && len(account.Resumes) == 0 {
+			account.Resume, account.Resumes = resume, resumes
+		}
+
+		if account.RateLimit == nil {
+			account.RateLimit = config.RateLimit
+		}
+	}
+
+	return config.Accounts, nil
+}
+
+// resumeTitles returns titles of resumes to apply with. The single resume goes first.
+func (a *AccountConfig) resumeTitles() []string {
+	titles := make([]string, 0, len(a.Resumes)+1)
+	seen := make(map[string]bool, len(a.Resumes)+1)
+
+	for _, title := range append([]string{a.Resume}, a.Resumes...) {
+		title = strings.TrimSpace(title)
+		if title == "" || seen[title] {
+			continue
+		}
+		seen[title] = true
+		titles = append(titles, title)
+	}
+
+	return titles
+}
+
+// selectAccounts returns all accounts or only the named one.
+func selectAccounts(config *Config, name string) ([]*AccountConfig, error) {
+	all, err := accounts(config)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return all, nil
+	}
+
+	names := make([]string, 0, len(all))
+	for _, account := range all {
+		if account.Name == name {
+			return []*AccountConfig{account}, nil
+		}
+		names = append(names, account.Name)
+	}
+
+	return nil, fmt.Errorf("account %s not found, configured accounts: %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+
+	"go.uber.org/zap"
+)
+
+// queuedSession returns a session enqueuing applications instead of sending them.
+func queuedSession(t *testing.T) *session {
+	t.Helper()
+
+	config := &Config{}
+	if err := json.Unmarshal([]byte(`{"Apply": {"Message": "configured message"}}`), config); err != nil {
+		t.Fatal(err)
+	}
+	config.Queue = &QueueConfig{Enabled: true, StateFile: filepath.Join(t.TempDir(), "queue.json")}
+
+	resume := &headhunter.Resume{ID: "r1", Title: "Go developer"}
+
+	return &session{
+		ctx:     context.Background(),
+		logger:  zap.NewNop(),
+		config:  config,
+		account: &AccountConfig{Name: "alice"},
+		resume:  resume,
+		resumes: []*headhunter.Resume{resume},
+	}
+}
+
+func TestEditedApplicationsAreQueued(t *testing.T) {
+	s := queuedSession(t)
+
+	frontends := map[string]func(*headhunter.Vacancy, string) error{
+		"review":    reviewActions(s).Apply,
+		"dashboard": dashboardDeps(nil, s, "").Apply,
+	}
+
+	for name, apply := range frontends {
+		edited, drafted := &headhunter.Vacancy{ID: name + "-edited"}, &headhunter.Vacancy{ID: name + "-drafted"}
+
+		if err := apply(edited, "edited message"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if err := apply(drafted, " "); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		state, err := s.config.Queue.store().Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for vacancy, message := range map[string]string{edited.ID: "edited message", drafted.ID: "configured message"} {
+			item := state.Find("alice", vacancy)
+			if item == nil {
+				t.Fatalf("%s: application to %s is not queued", name, vacancy)
+			}
+			if item.Message != message || item.ResumeID != "r1" {
+				t.Fatalf("%s: unexpected application to %s: %+v", name, vacancy, item)
+			}
+		}
+	}
+
+	if s.applied != 0 {
+		t.Fatalf("expected nothing sent, got %d applications", s.applied)
+	}
+}
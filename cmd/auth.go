@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spigell/hh-responder/internal/logger"
+	"github.com/spigell/hh-responder/internal/oauth"
+	"github.com/spigell/hh-responder/internal/secrets"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage hh.ru authorization",
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authorize hh-responder with the hh.ru OAuth flow and store the tokens",
+	Run: func(cmd *cobra.Command, _ []string) {
+		authLogin(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd)
+
+	authLoginCmd.Flags().String("account", "", "account to authorize. Required when several accounts are configured")
+}
+
+// authLogin authorizes the account and fills its token store.
+func authLogin(cmd *cobra.Command) {
+	logger, err := logger.New(viper.GetBool("json"), viper.GetBool("debug"))
+	if err != nil {
+		log.Fatalf("creating a logger: %s", err)
+	}
+
+	config, err := getConfig()
+	if err != nil {
+		logger.Fatal("getting a config", zap.Error(err))
+	}
+
+	selected, err := selectAccounts(config, cmd.Flag("account").Value.String())
+	if err != nil {
+		logger.Fatal("selecting account", zap.Error(err))
+	}
+	if len(selected) > 1 {
+		logger.Fatal("several accounts configured, choose the one to authorize with --account")
+	}
+
+	account := selected[0]
+	logger = logger.With(zap.String("account", account.Name))
+
+	client, store, err := newOAuth(account.OAuth, config.API, config.HTTP)
+	if err != nil {
+		logger.Fatal("preparing oauth", zap.Error(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	token, err := client.Login(ctx, func(authURL string) {
+		fmt.Fprintf(os.Stderr, "Open the following page in a browser and allow access:\n\n%s\n\n", authURL)
+	})
+	if err != nil {
+		logger.Fatal("authorization failed", zap.Error(err))
+	}
+
+	if err := store.Save(token); err != nil {
+		logger.Fatal("saving tokens", zap.Error(err))
+	}
+
+	logger.Info("authorized successfully", zap.String("token_store", store.Path), zap.Time("expires_at", token.ExpiresAt))
+}
+
+// newOAuth creates the OAuth client and the token store from the config.
+func newOAuth(config *OAuthConfig, api *APIConfig, httpConfig *HTTPConfig) (*oauth.Client, *oauth.Store, error) {
+	if config == nil || strings.TrimSpace(config.ClientID) == "" {
+		return nil, nil, errors.New("oauth.client-id is not configured")
+	}
+
+	if strings.TrimSpace(config.TokenStore) == "" {
+		return nil, nil, errors.New("oauth.token-store is not configured")
+	}
+
+	// The secret is needed only to exchange the authorization code. Refreshing works without it.
+	var secret string
+	if strings.TrimSpace(config.ClientSecretFile) != "" {
+		var err error
+		secret, err = secrets.Load(secrets.Source{Name: "oauth client secret", File: config.ClientSecretFile})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	oauthConfig := oauth.Config{
+		ClientID:     strings.TrimSpace(config.ClientID),
+		ClientSecret: secret,
+		RedirectURL:  strings.TrimSpace(config.RedirectURL),
+	}
+	api.configureOAuth(&oauthConfig)
+
+	client := oauth.New(oauthConfig)
+	if err := httpConfig.configure(client.HTTPClient); err != nil {
+		return nil, nil, err
+	}
+
+	return client, &oauth.Store{Path: config.TokenStore}, nil
+}
+
+// oauthToken returns the stored access token refreshing it in advance when it is expired.
+func oauthToken(ctx context.Context, config *OAuthConfig, api *APIConfig, httpConfig *HTTPConfig, logger *zap.Logger) (string, *oauth.Refresher, error) {
+	client, store, err := newOAuth(config, api, httpConfig)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := store.Load()
+	if err != nil {
+		return "", nil, err
+	}
+
+	refresher := oauth.NewRefresher(client, store, logger)
+
+	if token.Expired() {
+		logger.Info("stored access token is expired", zap.Time("expires_at", token.ExpiresAt))
+
+		access, err := refresher.Refresh(ctx, token.AccessToken)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return access, refresher, nil
+	}
+
+	return token.AccessToken, refresher, nil
+}
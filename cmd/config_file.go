@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spigell/hh-responder/internal/utils"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// setConfigValue sets the key of the section in the yaml config file. Comments and the order
+// of other keys are kept. A list replaces the current value completely.
+func setConfigValue(path, section, key string, values []string, list bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("config %s is not a mapping", path)
+	}
+
+	sectionNode := mappingValue(doc.Content[0], section)
+	if sectionNode.Kind != yaml.MappingNode {
+		*sectionNode = yaml.Node{Kind: yaml.MappingNode, HeadComment: sectionNode.HeadComment, LineComment: sectionNode.LineComment}
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode}
+	switch {
+	case list:
+		value = &yaml.Node{Kind: yaml.SequenceNode}
+		for _, v := range values {
+			value.Content = append(value.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v})
+		}
+	case len(values) > 0:
+		value.Value = values[0]
+	}
+
+	node := mappingValue(sectionNode, key)
+	value.LineComment = node.LineComment
+	*node = *value
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(path, out.Bytes(), info.Mode().Perm())
+}
+
+// mappingValue returns the value node of the key, adding an empty one when the key is missing.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+
+	return value
+}
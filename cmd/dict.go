@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spigell/hh-responder/internal/dictionary"
+	"github.com/spigell/hh-responder/internal/headhunter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var dictCmd = &cobra.Command{
+	Use:   "dict",
+	Short: "Look up hh.ru dictionaries: areas, schedules, professional roles, industries and others",
+}
+
+var dictSearchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Find dictionary ids by a part of the name or the id",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dictSearch(cmd, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dictCmd)
+	dictCmd.AddCommand(dictSearchCmd)
+
+	dictSearchCmd.Flags().String("kind", "", "search only in the dictionary, e.g. area, schedule, professional_role or industry")
+	dictSearchCmd.Flags().Bool("refresh", false, "load dictionaries from hh.ru ignoring the cache")
+	dictSearchCmd.Flags().String("locale", "", "load names in the locale too, e.g. KZ. Names in RU and EN are always loaded. Default is api.locale")
+}
+
+func dictSearch(cmd *cobra.Command, term string) {
+	logger, config := setup()
+
+	// Dictionaries are public, the site and the network still come from the config.
+	hh, err := newClient(context.Background(), logger, config, "")
+	if err != nil {
+		logger.Fatal("preparing the client", zap.Error(err))
+	}
+
+	locale := cmd.Flag("locale").Value.String()
+	if locale == "" {
+		locale = config.API.locale()
+	}
+
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	dict, err := dictionaryCache(hh, locale).Load(hh, refresh)
+	if err != nil {
+		logger.Fatal("loading dictionaries", zap.Error(err))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tID\tNAME\tPARENT")
+	for _, entry := range dict.Search(term, cmd.Flag("kind").Value.String()) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Kind, entry.ID, entry.Name(), entry.Parent)
+	}
+
+	if err := w.Flush(); err != nil {
+		logger.Fatal("writing dictionary entries", zap.Error(err))
+	}
+}
+
+// dictionaryCache returns the cache of dictionaries from the API and the site of the client.
+func dictionaryCache(hh *headhunter.Client, locale string) *dictionary.Cache {
+	host := hh.Host
+	if host == "" {
+		host = headhunter.DefaultHost
+	}
+
+	return &dictionary.Cache{
+		Path:    dictionary.DefaultCachePath(),
+		Locales: dictionary.LocalesWith(locale),
+		Source:  hh.APIURL + " " + host,
+	}
+}
+
+// resolveSearch replaces names in the search parameters with dictionary ids. When the dictionaries
+// can't be loaded the values are used as is, since they are likely ids already.
+func resolveSearch(hh *headhunter.Client, config *Config, logger *zap.Logger) error {
+	search := config.Search
+	if search == nil {
+		return nil
+	}
+
+	dict, err := dictionaryCache(hh, config.API.locale()).Load(hh, false)
+	if err != nil {
+		logger.Warn("dictionaries are not available, search values are used as ids", zap.Error(err))
+		return nil
+	}
+
+	lists := []struct {
+		kind   string
+		values []string
+	}{
+		{dictionary.KindArea, search.Areas},
+		{dictionary.KindProfessionalRole, search.ProfessionalRoles},
+		{dictionary.KindIndustry, search.Industries},
+		{"schedule", search.Schedules},
+		{"employment", search.Employments},
+		{"vacancy_label", search.Labels},
+		{"vacancy_search_fields", search.SearchField},
+	}
+	for _, list := range lists {
+		for i, value := range list.values {
+			id, err := resolveName(dict, list.kind, value, logger)
+			if err != nil {
+				return err
+			}
+			list.values[i] = id
+		}
+	}
+
+	singles := []struct {
+		kind  string
+		value *string
+	}{
+		{"experience", &search.Experience},
+		{"currency", &search.Currency},
+		{"vacancy_search_order", &search.OrderBy},
+	}
+	for _, single := range singles {
+		if *single.value == "" {
+			continue
+		}
+
+		id, err := resolveName(dict, single.kind, *single.value, logger)
+		if err != nil {
+			return err
+		}
+		*single.value = id
+	}
+
+	return nil
+}
+
+func resolveName(dict *dictionary.Dictionary, kind, value string, logger *zap.Logger) (string, error) {
+	id, err := dict.Resolve(kind, value)
+	switch {
+	case errors.Is(err, dictionary.ErrNotLoaded):
+		logger.Debug("dictionary is missing, the value is used as is", zap.String("kind", kind), zap.String("value", value))
+		return value, nil
+	case err != nil:
+		return "", fmt.Errorf("%w (look ids up with 'hh-responder dict search')", err)
+	}
+
+	if id != value {
+		logger.Debug("resolved search value", zap.String("kind", kind), zap.String("name", value), zap.String("id", id))
+	}
+
+	return id, nil
+}
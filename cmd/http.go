@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spigell/hh-responder/internal/transport"
+)
+
+// httpTransports are shared by all clients of the process, so the trace file is opened once
+// and traces of concurrent requests don't interleave.
+var httpTransports struct {
+	sync.Mutex
+	base   *http.Transport
+	traced http.RoundTripper
+}
+
+// transport returns the transport of the configured proxy and CA bundle. Traced transports dump
+// requests to the trace file when it is set.
+func (c *HTTPConfig) transport(traced bool) (http.RoundTripper, error) {
+	httpTransports.Lock()
+	defer httpTransports.Unlock()
+
+	if httpTransports.base == nil {
+		var cfg transport.Config
+		if c != nil {
+			cfg = transport.Config{Proxy: c.Proxy, CAFile: c.CAFile}
+		}
+
+		base, err := transport.New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("preparing http transport: %w", err)
+		}
+		httpTransports.base = base
+	}
+
+	if !traced || c == nil || strings.TrimSpace(c.TraceFile) == "" {
+		return httpTransports.base, nil
+	}
+
+	if httpTransports.traced == nil {
+		file, err := os.OpenFile(c.TraceFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("opening http trace file: %w", err)
+		}
+		httpTransports.traced = transport.NewTracer(httpTransports.base, file)
+	}
+
+	return httpTransports.traced, nil
+}
+
+// configure applies the transport and the timeout to a client of the hh.ru API.
+func (c *HTTPConfig) configure(client *http.Client) error {
+	rt, err := c.transport(true)
+	if err != nil {
+		return err
+	}
+
+	client.Transport = rt
+	if c != nil && c.Timeout > 0 {
+		client.Timeout = c.Timeout
+	}
+
+	return nil
+}
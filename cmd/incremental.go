@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/incremental"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const defaultStateFile = "hh-responder-state.json"
+
+type IncrementalConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// StateFile keeps the last successful search per account and search.
+	StateFile string `mapstructure:"state-file"`
+	// FullScanEvery is the number of runs after which the whole period is searched again.
+	FullScanEvery int `mapstructure:"full-scan-every"`
+}
+
+// pendingSearch is the search to be recorded in the state once the run succeeds.
+type pendingSearch struct {
+	plan      incremental.Plan
+	startedAt time.Time
+}
+
+func (c *IncrementalConfig) store() *incremental.Store {
+	path := c.StateFile
+	if path == "" {
+		path = defaultStateFile
+	}
+
+	return &incremental.Store{Path: path}
+}
+
+// searchParams returns the search of the session. With incremental search enabled only vacancies
+// published since the last successful search are requested.
+func (s *session) searchParams(cmd *cobra.Command) *headhunter.SearchParams {
+	cfg := s.config.Incremental
+	if cfg == nil || !cfg.Enabled {
+		return s.config.Search
+	}
+
+	state, err := cfg.store().Load()
+	if err != nil {
+		s.logger.Warn("incremental search state is not available, searching the whole period", zap.Error(err))
+		return s.config.Search
+	}
+
+	period := time.Duration(s.config.Search.Period) * 24 * time.Hour
+	plan := state.Plan(incremental.Key(s.account.Name, s.config.Search), cfg.FullScanEvery, period)
+
+	if cmd != nil {
+		if full, _ := cmd.Flags().GetBool("full-scan"); full {
+			plan.Since = time.Time{}
+		}
+	}
+
+	s.search = &pendingSearch{plan: plan, startedAt: time.Now()}
+
+	if plan.Full() {
+		s.logger.Info("full search", zap.String("profile", plan.Key))
+		return s.config.Search
+	}
+
+	params := *s.config.Search
+	params.Period = 0
+	params.DateFrom = headhunter.FormatTime(plan.Since)
+
+	s.logger.Info("incremental search", zap.String("profile", plan.Key), zap.Time("since", plan.Since))
+	return &params
+}
+
+// commitSearch records the successful search for the next incremental run.
+func (s *session) commitSearch() {
+	if s.search == nil {
+		return
+	}
+
+	search := s.search
+	s.search = nil
+
+	err := s.config.Incremental.store().Update(func(state *incremental.State) {
+		state.Done(search.plan, search.startedAt)
+	})
+	if err != nil {
+		s.logger.Warn("saving incremental search state", zap.Error(err))
+	}
+}
+
+// discardSearch keeps the incremental state as it is, so the next run searches again the vacancies
+// the run found but could not handle.
+func (s *session) discardSearch(reason string) {
+	if s.search == nil {
+		return
+	}
+
+	s.search = nil
+	s.logger.Info("incremental search state is not updated", zap.String("reason", reason))
+}
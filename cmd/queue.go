@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/queue"
+	"github.com/spigell/hh-responder/internal/report"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultQueueFile     = "hh-responder-queue.json"
+	defaultQueueInterval = 2 * time.Minute
+	defaultQueueJitter   = time.Minute
+)
+
+type QueueConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// StateFile keeps the queued applications.
+	StateFile string `mapstructure:"state-file"`
+	// Interval is the minimal pause between applications, Jitter is the random delay added to it.
+	Interval time.Duration `mapstructure:"interval"`
+	Jitter   time.Duration `mapstructure:"jitter"`
+	// MaxPerHour limits applications in a rolling hour. Zero means no limit.
+	MaxPerHour int `mapstructure:"max-per-hour"`
+	// WorkingHours is the local time window applications are sent in, e.g. 09:00-19:00. Empty means any time.
+	WorkingHours string `mapstructure:"working-hours"`
+	// MaxAttempts is the number of tries of an application failing with temporary errors.
+	MaxAttempts int `mapstructure:"max-attempts"`
+}
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Inspect and manage applications waiting to be sent",
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued applications with their attempts and errors",
+	Run: func(cmd *cobra.Command, _ []string) {
+		queueList(cmd)
+	},
+}
+
+var queueDropCmd = &cobra.Command{
+	Use:   "drop <vacancy-id>...",
+	Short: "Remove applications to the vacancies from the queue",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		queueDrop(cmd, args)
+	},
+}
+
+var queueFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Remove all queued applications",
+	Run: func(cmd *cobra.Command, _ []string) {
+		queueFlush(cmd)
+	},
+}
+
+var queueWorkCmd = &cobra.Command{
+	Use:   "work",
+	Short: "Send queued applications at the configured pace until the queue is empty",
+	Run: func(cmd *cobra.Command, _ []string) {
+		queueWork(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueListCmd, queueDropCmd, queueFlushCmd, queueWorkCmd)
+
+	queueCmd.PersistentFlags().String("account", "", "work only with applications of the account. Default is all accounts")
+	queueFlushCmd.Flags().Bool("failed", false, "remove only failed applications")
+}
+
+func (c *QueueConfig) store() *queue.Store {
+	path := ""
+	if c != nil {
+		path = c.StateFile
+	}
+	if path == "" {
+		path = defaultQueueFile
+	}
+
+	return &queue.Store{Path: path}
+}
+
+// schedule returns the pace of the worker.
+func (c *QueueConfig) schedule() (queue.Schedule, error) {
+	schedule := queue.Schedule{Interval: defaultQueueInterval, Jitter: defaultQueueJitter}
+	if c == nil {
+		return schedule, nil
+	}
+
+	if c.Interval > 0 {
+		schedule.Interval = c.Interval
+	}
+	if c.Jitter > 0 {
+		schedule.Jitter = c.Jitter
+	}
+	schedule.MaxPerHour = c.MaxPerHour
+
+	var err error
+	schedule.From, schedule.To, err = queue.ParseHours(c.WorkingHours)
+
+	return schedule, err
+}
+
+// enqueue puts applications to the vacancies with their messages in the queue for the worker.
+func (s *session) enqueue(vacancies *headhunter.Vacancies, messageFor func(*headhunter.Vacancy) string) error {
+	items := make([]*queue.Item, 0, vacancies.Len())
+	for _, vacancy := range vacancies.Items {
+		items = append(items, queue.NewItem(s.account.Name, vacancy, s.resumeFor(vacancy), messageFor(vacancy)))
+	}
+
+	added := 0
+	err := s.config.Queue.store().Update(func(state *queue.State) {
+		for _, item := range items {
+			if state.Add(item) {
+				added++
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("enqueuing applications: %w", err)
+	}
+
+	if s.result != nil {
+		for _, vacancy := range vacancies.Items {
+			s.result.Queued = append(s.result.Queued, report.NewResultVacancy(vacancy))
+		}
+	}
+
+	s.logger.Info("applications enqueued",
+		zap.Int("added", added),
+		zap.Int("already_queued", len(items)-added),
+		zap.String("hint", "send them with 'hh-responder queue work'"),
+	)
+
+	return nil
+}
+
+// queued returns the vacancies of the account waiting in the queue and the number of applications
+// among them still to be sent. It is empty when the queue is disabled.
+func (s *session) queued() (ids []string, pending int) {
+	cfg := s.config.Queue
+	if cfg == nil || !cfg.Enabled {
+		return nil, 0
+	}
+
+	state, err := cfg.store().Load()
+	if err != nil {
+		s.logger.Warn("loading the queue", zap.Error(err))
+		return nil, 0
+	}
+
+	for _, item := range state.Items {
+		if item.Account != s.account.Name {
+			continue
+		}
+
+		ids = append(ids, item.VacancyID)
+		if !item.Failed {
+			pending++
+		}
+	}
+
+	return ids, pending
+}
+
+// matchAccount returns the filter of items by the account flag.
+func matchAccount(cmd *cobra.Command) func(*queue.Item) bool {
+	account := cmd.Flag("account").Value.String()
+	return func(item *queue.Item) bool {
+		return account == "" || item.Account == account
+	}
+}
+
+func queueList(cmd *cobra.Command) {
+	logger, config := setup()
+
+	state, err := config.Queue.store().Load()
+	if err != nil {
+		logger.Fatal("loading the queue", zap.Error(err))
+	}
+
+	match := matchAccount(cmd)
+	now := time.Now()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tVACANCY\tNAME\tEMPLOYER\tRESUME\tENQUEUED\tATTEMPTS\tSTATUS")
+	for _, item := range state.Items {
+		if !match(item) {
+			continue
+		}
+
+		status := "pending"
+		switch {
+		case item.Failed:
+			status = "failed: " + item.LastError
+		case !item.Due(now):
+			status = "retry at " + item.NextAttemptAt.Local().Format(time.DateTime) + ": " + item.LastError
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			item.Account, item.VacancyID, item.VacancyName, item.EmployerName, item.ResumeTitle,
+			item.EnqueuedAt.Local().Format(time.DateTime), item.Attempts, status)
+	}
+
+	if err := w.Flush(); err != nil {
+		logger.Fatal("writing the queue", zap.Error(err))
+	}
+}
+
+func queueDrop(cmd *cobra.Command, ids []string) {
+	logger, config := setup()
+	match := matchAccount(cmd)
+
+	var removed []*queue.Item
+	err := config.Queue.store().Update(func(state *queue.State) {
+		removed = state.Remove(func(item *queue.Item) bool {
+			if !match(item) {
+				return false
+			}
+			for _, id := range ids {
+				if item.VacancyID == id {
+					return true
+				}
+			}
+			return false
+		})
+	})
+	if err != nil {
+		logger.Fatal("dropping applications", zap.Error(err))
+	}
+
+	for _, item := range removed {
+		logger.Info("application dropped", zap.String("account", item.Account), zap.String("vacancy_id", item.VacancyID))
+	}
+
+	if len(removed) == 0 {
+		logger.Warn("no queued applications to the vacancies", zap.Strings("vacancy_ids", ids))
+	}
+}
+
+func queueFlush(cmd *cobra.Command) {
+	logger, config := setup()
+	match := matchAccount(cmd)
+	failedOnly, _ := cmd.Flags().GetBool("failed")
+
+	var removed []*queue.Item
+	err := config.Queue.store().Update(func(state *queue.State) {
+		removed = state.Remove(func(item *queue.Item) bool {
+			return match(item) && (!failedOnly || item.Failed)
+		})
+	})
+	if err != nil {
+		logger.Fatal("flushing the queue", zap.Error(err))
+	}
+
+	logger.Info("queue flushed", zap.Int("removed", len(removed)))
+}
+
+// queueWork sends queued applications. Accounts are opened on their first application. Applications
+// of accounts out of their budget are left in the queue for the next time.
+func queueWork(cmd *cobra.Command) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger, config := setup()
+
+	schedule, err := config.Queue.schedule()
+	if err != nil {
+		logger.Fatal("preparing the schedule", zap.Error(err))
+	}
+
+	configured, err := selectAccounts(config, cmd.Flag("account").Value.String())
+	if err != nil {
+		logger.Fatal("selecting accounts", zap.Error(err))
+	}
+
+	sessions := make(map[string]*session, len(configured))
+	open := func(name string) (*session, error) {
+		if s, ok := sessions[name]; ok {
+			return s, nil
+		}
+
+		for _, account := range configured {
+			if account.Name != name {
+				continue
+			}
+
+			accountLogger := logger.With(zap.String("account", name))
+			hh, err := openClient(ctx, accountLogger, config, account)
+			if err != nil {
+				return nil, err
+			}
+
+			s := &session{ctx: ctx, logger: accountLogger, config: config, account: account, hh: hh}
+			s.loadQuota()
+			sessions[name] = s
+
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("account %q is not configured", name)
+	}
+
+	submit := func(_ context.Context, item *queue.Item) error {
+		s, err := open(item.Account)
+		if err != nil {
+			return fmt.Errorf("%w: %w", queue.ErrHold, err)
+		}
+
+		vacancy := &headhunter.Vacancy{ID: item.VacancyID, Name: item.VacancyName}
+		resume := &headhunter.Resume{ID: item.ResumeID, Title: item.ResumeTitle}
+
+		err = s.applyWithResume(vacancy, resume, item.Message)
+		if outOfBudget(err) {
+			return fmt.Errorf("%w: %w", queue.ErrHold, err)
+		}
+
+		return err
+	}
+
+	worker := queue.NewWorker(config.Queue.store(), schedule, submit, logger)
+	if config.Queue != nil && config.Queue.MaxAttempts > 0 {
+		worker.MaxAttempts = config.Queue.MaxAttempts
+	}
+
+	// Applications of other accounts stay in the queue.
+	worker.Match = matchAccount(cmd)
+
+	stats, err := worker.Run(ctx)
+
+	logger.Info("queue worker finished",
+		zap.Int("sent", stats.Sent),
+		zap.Int("retried", stats.Retried),
+		zap.Int("failed", stats.Failed),
+		zap.Int("held", stats.Held),
+	)
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		logger.Fatal("sending queued applications", zap.Error(err))
+	}
+}
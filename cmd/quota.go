@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/quota"
+	"github.com/spigell/hh-responder/internal/report"
+
+	"go.uber.org/zap"
+)
+
+const defaultQuotaFile = "hh-responder-quota.json"
+
+var errDailyQuota = errors.New("daily quota of applications is exhausted")
+
+type QuotaConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// StateFile keeps applications of the last 24 hours and vacancies carried over per account.
+	StateFile string `mapstructure:"state-file"`
+}
+
+// dailyQuota is the budget of the session when the quota is enabled.
+type dailyQuota struct {
+	store     *quota.Store
+	limit     int
+	remaining int
+}
+
+func (c *QuotaConfig) store() *quota.Store {
+	path := c.StateFile
+	if path == "" {
+		path = defaultQuotaFile
+	}
+
+	return &quota.Store{Path: path}
+}
+
+// dailyLimit returns the number of applications the account may send in 24 hours.
+func (a *AccountConfig) dailyLimit() int {
+	if a.RateLimit == nil || a.RateLimit.DailyApplications <= 0 {
+		return quota.DefaultDailyLimit
+	}
+	return a.RateLimit.DailyApplications
+}
+
+// withCarried loads the quota of the session and puts vacancies carried over by previous runs
+// before the found ones. Carried vacancies go through the filters again but keep their AI assessment.
+func (s *session) withCarried(vacancies *headhunter.Vacancies) *headhunter.Vacancies {
+	account := s.loadQuota()
+	if account == nil {
+		return vacancies
+	}
+
+	// Expired vacancies are left out of the state by the next setCarried.
+	if expired := account.Expire(s.carriedAge(), time.Now()); expired > 0 {
+		s.logger.Info("dropping vacancies carried over for too long", zap.Int("count", expired), zap.Duration("max_age", s.carriedAge()))
+	}
+	if len(account.Carried) == 0 {
+		return vacancies
+	}
+
+	carried := make(map[string]bool, len(account.Carried))
+	merged := &headhunter.Vacancies{Items: make([]*headhunter.Vacancy, 0, len(account.Carried)+vacancies.Len())}
+	for _, vacancy := range account.CarriedVacancies() {
+		carried[vacancy.ID] = true
+		merged.Items = append(merged.Items, vacancy)
+	}
+	for _, vacancy := range vacancies.Items {
+		if !carried[vacancy.ID] {
+			merged.Items = append(merged.Items, vacancy)
+		}
+	}
+
+	return merged
+}
+
+// carriedAge returns how long vacancies are carried over: the search period or the longest one when
+// the search uses dates.
+func (s *session) carriedAge() time.Duration {
+	if period := time.Duration(s.config.Search.Period) * 24 * time.Hour; period > 0 {
+		return period
+	}
+	return quota.DefaultCarriedAge
+}
+
+// loadQuota sets the budget of the session and returns the quota state of the account.
+// It returns nil when the quota is disabled or not available.
+func (s *session) loadQuota() *quota.Account {
+	cfg := s.config.Quota
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	store := cfg.store()
+	state, err := store.Load()
+	if err != nil {
+		s.logger.Warn("quota state is not available, applications are not limited", zap.Error(err))
+		return nil
+	}
+
+	account := state.Account(s.account.Name)
+	s.quota = &dailyQuota{
+		store:     store,
+		limit:     s.account.dailyLimit(),
+		remaining: account.Remaining(s.account.dailyLimit(), time.Now()),
+	}
+
+	s.logger.Info("daily quota",
+		zap.Int("limit", s.quota.limit),
+		zap.Int("remaining", s.quota.remaining),
+		zap.Int("carried", len(account.Carried)),
+	)
+
+	return account
+}
+
+// fitQuota leaves the vacancies the remaining budget allows, favourite employers and the best AI
+// scores first. The rest is carried over to the next run. Vacancies already queued are left out and
+// their applications are taken from the budget.
+func (s *session) fitQuota(vacancies *headhunter.Vacancies) {
+	if s.quota == nil {
+		return
+	}
+
+	var favorites []string
+	if s.config.Apply.Favorites != nil {
+		favorites = s.config.Apply.Favorites.Employers
+	}
+
+	// Queued applications take the budget already, the worker sends them later.
+	remaining := s.quota.remaining
+	if ids, pending := s.queued(); len(ids) > 0 {
+		vacancies.Exclude(headhunter.VacancyIDField, ids)
+		remaining = max(remaining-pending, 0)
+		s.logger.Info("daily quota is reduced by queued applications", zap.Int("queued", pending), zap.Int("remaining", remaining))
+	}
+
+	quota.Prioritize(vacancies.Items, favorites)
+
+	fit, overflow := quota.Split(vacancies.Items, remaining)
+	vacancies.Items = fit
+
+	if len(overflow) > 0 {
+		s.logger.Info("vacancies exceed the daily quota and are carried over to the next run",
+			zap.Int("remaining", remaining),
+			zap.Int("carried", len(overflow)),
+		)
+	}
+
+	s.setCarried(overflow)
+}
+
+// setCarried replaces vacancies carried over to the next run.
+func (s *session) setCarried(vacancies []*headhunter.Vacancy) {
+	if s.quota == nil {
+		return
+	}
+
+	err := s.quota.store.Update(s.account.Name, func(account *quota.Account) {
+		account.Carry(vacancies, time.Now())
+	})
+	if err != nil {
+		s.logger.Warn("saving carried vacancies", zap.Error(err))
+	}
+
+	if s.result != nil {
+		s.result.Carried = make([]report.ResultVacancy, 0, len(vacancies))
+		for _, vacancy := range vacancies {
+			s.result.Carried = append(s.result.Carried, report.NewResultVacancy(vacancy))
+		}
+	}
+}
+
+// carryOver adds vacancies the quota did not allow to apply to the carried ones.
+func (s *session) carryOver(vacancies []*headhunter.Vacancy) {
+	if s.quota == nil || len(vacancies) == 0 {
+		return
+	}
+
+	state, err := s.quota.store.Load()
+	if err != nil {
+		s.logger.Warn("loading carried vacancies", zap.Error(err))
+		return
+	}
+
+	carried := slices.Clone(vacancies)
+	for _, vacancy := range state.Account(s.account.Name).CarriedVacancies() {
+		// The same vacancy may be tried again, e.g. in the dashboard.
+		if !slices.ContainsFunc(vacancies, func(v *headhunter.Vacancy) bool { return v.ID == vacancy.ID }) {
+			carried = append(carried, vacancy)
+		}
+	}
+
+	s.setCarried(carried)
+}
+
+// uncarry drops the vacancy handled outside of the run, e.g. in the dashboard, from the carried ones.
+func (s *session) uncarry(vacancy *headhunter.Vacancy) {
+	if s.quota == nil {
+		return
+	}
+
+	err := s.quota.store.Update(s.account.Name, func(account *quota.Account) {
+		account.Uncarry(vacancy.ID)
+	})
+	if err != nil {
+		s.logger.Warn("saving carried vacancies", zap.Error(err))
+	}
+}
+
+// checkQuota returns an error when the session can't send more applications today.
+func (s *session) checkQuota() error {
+	if s.quota == nil || s.quota.remaining > 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d applications in 24 hours", errDailyQuota, s.quota.limit)
+}
+
+// recordApplication counts the application sent or stops the quota when hh.ru reports the limit.
+func (s *session) recordApplication(err error) error {
+	switch {
+	case err == nil:
+		if s.quota == nil {
+			return nil
+		}
+
+		s.quota.remaining--
+		if err := s.quota.store.Update(s.account.Name, func(account *quota.Account) {
+			account.Record(time.Now())
+		}); err != nil {
+			s.logger.Warn("saving quota state", zap.Error(err))
+		}
+		return nil
+	case errors.Is(err, headhunter.ErrLimitExceeded):
+		if s.quota != nil {
+			s.quota.remaining = 0
+			if err := s.quota.store.Update(s.account.Name, func(account *quota.Account) {
+				account.Exhaust(time.Now())
+			}); err != nil {
+				s.logger.Warn("saving quota state", zap.Error(err))
+			}
+		}
+		return fmt.Errorf("%w: %w", errDailyQuota, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/ai"
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/report"
+	"github.com/spigell/hh-responder/internal/utils"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAdviseLimit = 30
+	maxAdviseSkills    = 15
+	// Failed touches in the loop are retried after a pause doubling up to the maximum one.
+	touchRetryDelay    = time.Minute
+	maxTouchRetryDelay = 30 * time.Minute
+)
+
+var resumesCmd = &cobra.Command{
+	Use:   "resumes",
+	Short: "Manage resumes of the hh.ru account",
+}
+
+var resumesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List resumes with their status, views and the next time they can be raised in search",
+	Run: func(cmd *cobra.Command, _ []string) {
+		resumesList(cmd)
+	},
+}
+
+var resumesShowCmd = &cobra.Command{
+	Use:   "show <id|title>",
+	Short: "Print a readable rendering of the resume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resumesShow(cmd, args[0])
+	},
+}
+
+var resumesPublishCmd = &cobra.Command{
+	Use:   "publish <id|title>...",
+	Short: "Raise resumes in search results once",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resumesPublish(cmd, args)
+	},
+}
+
+var resumesTouchCmd = &cobra.Command{
+	Use:   "touch [id|title]...",
+	Short: "Raise resumes in search as soon as the hh.ru cooldown allows. Default is all published resumes",
+	Run: func(cmd *cobra.Command, args []string) {
+		resumesTouch(cmd, args)
+	},
+}
+
+var resumesAdviseCmd = &cobra.Command{
+	Use:   "advise <id|title>",
+	Short: "Compare the resume with recent vacancies using AI and suggest improvements",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resumesAdvise(cmd, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resumesCmd)
+	resumesCmd.AddCommand(resumesListCmd, resumesShowCmd, resumesPublishCmd, resumesTouchCmd, resumesAdviseCmd)
+
+	resumesCmd.PersistentFlags().String("account", "", "account to manage. Default is the first configured one")
+	resumesTouchCmd.Flags().Bool("loop", false, "keep running and raise resumes every time the cooldown expires")
+	resumesAdviseCmd.Flags().String("from-result", "", "take kept and applied vacancies from the run result file instead of searching")
+	resumesAdviseCmd.Flags().Int("limit", defaultAdviseLimit, "maximum number of vacancies to analyse")
+}
+
+// resumesClient prepares the headhunter client of the chosen account. Like the rest of the cli it exits on any error.
+func resumesClient(ctx context.Context, cmd *cobra.Command) (*zap.Logger, *headhunter.Client) {
+	logger, config := setup()
+	account := singleAccount(cmd, logger, config)
+
+	hh, err := openClient(ctx, logger.With(zap.String("account", account.Name)), config, account)
+	if err != nil {
+		logger.Fatal("opening account", zap.String("account", account.Name), zap.Error(err))
+	}
+
+	return logger, hh
+}
+
+func resumesList(cmd *cobra.Command) {
+	logger, hh := resumesClient(context.Background(), cmd)
+
+	resumes, err := hh.GetMineResumes()
+	if err != nil {
+		logger.Fatal("getting resumes", zap.Error(err))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tUPDATED\tVIEWS\tNEXT PUBLISH")
+	for _, resume := range resumes.Items {
+		status := "-"
+		if resume.Status != nil {
+			status = resume.Status.Name
+		}
+
+		next := "now"
+		if t := resume.NextPublish(); !t.IsZero() {
+			next = t.Local().Format(time.DateTime)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d (+%d)\t%s\n", resume.ID, resume.Title, status, resume.UpdatedAt, resume.TotalViews, resume.NewViews, next)
+	}
+
+	if err := w.Flush(); err != nil {
+		logger.Fatal("writing resumes", zap.Error(err))
+	}
+}
+
+func resumesShow(cmd *cobra.Command, idOrTitle string) {
+	logger, hh := resumesClient(context.Background(), cmd)
+
+	_, raw := mustGetResume(logger, hh, idOrTitle)
+
+	fmt.Print(headhunter.ResumeText(raw))
+}
+
+// mustGetResume returns the resume found by id or title with its raw payload. It exits on any error.
+func mustGetResume(logger *zap.Logger, hh *headhunter.Client, idOrTitle string) (*headhunter.Resume, map[string]any) {
+	resumes, err := hh.GetMineResumes()
+	if err != nil {
+		logger.Fatal("getting resumes", zap.Error(err))
+	}
+
+	resume := resumes.Find(idOrTitle)
+	if resume == nil {
+		logger.Fatal("resume not found", zap.String("resume", idOrTitle))
+	}
+
+	raw, err := hh.GetResumeRaw(resume.ID)
+	if err != nil {
+		logger.Fatal("getting resume", zap.String("resume_id", resume.ID), zap.Error(err))
+	}
+
+	return resume, raw
+}
+
+func resumesPublish(cmd *cobra.Command, args []string) {
+	logger, hh := resumesClient(context.Background(), cmd)
+
+	resumes, err := hh.GetMineResumes()
+	if err != nil {
+		logger.Fatal("getting resumes", zap.Error(err))
+	}
+
+	targets, err := findResumes(resumes, args)
+	if err != nil {
+		logger.Fatal("choosing resumes", zap.Error(err))
+	}
+
+	failed := false
+	for _, resume := range targets {
+		log := logger.With(zap.String("resume_id", resume.ID), zap.String("resume_title", resume.Title))
+		if err := hh.PublishResume(resume.ID); err != nil {
+			if next := resume.NextPublish(); !next.IsZero() {
+				log = log.With(zap.Time("next_publish_at", next))
+			}
+			log.Error("publishing resume", zap.Error(err))
+			failed = true
+			continue
+		}
+
+		log.Info("resume raised in search")
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func resumesTouch(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger, hh := resumesClient(ctx, cmd)
+	loop, _ := cmd.Flags().GetBool("loop")
+
+	failures := 0
+	for {
+		next, err := touchResumes(logger, hh, args)
+		switch {
+		case err != nil && !loop:
+			logger.Fatal("touching resumes", zap.Error(err))
+		case err != nil:
+			// The loop keeps resumes fresh unattended, a network failure must not stop it.
+			failures++
+			delay := min(touchRetryDelay<<min(failures-1, 10), maxTouchRetryDelay)
+			next = time.Now().Add(delay)
+			logger.Error("touching resumes, retrying", zap.Error(err), zap.Int("failures", failures), zap.Duration("delay", delay))
+		case !loop:
+			return
+		default:
+			failures = 0
+			logger.Info("waiting for the next touch", zap.Time("next_touch_at", next))
+		}
+
+		if err := utils.WaitFor(ctx, time.Until(next)); err != nil {
+			logger.Info("stopped", zap.Error(err))
+			return
+		}
+	}
+}
+
+// touchResumes raises every resume whose cooldown has expired and returns the earliest time the next one may be raised.
+func touchResumes(logger *zap.Logger, hh *headhunter.Client, args []string) (time.Time, error) {
+	resumes, err := hh.GetMineResumes()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting resumes: %w", err)
+	}
+
+	targets, err := findResumes(resumes, args)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	earliest := now.Add(headhunter.PublishCooldown)
+	for _, resume := range targets {
+		log := logger.With(zap.String("resume_id", resume.ID), zap.String("resume_title", resume.Title))
+
+		if next := resume.NextPublish(); next.After(now) {
+			log.Info("skipping resume until the cooldown expires", zap.Time("next_publish_at", next))
+			if next.Before(earliest) {
+				earliest = next
+			}
+			continue
+		}
+
+		err := hh.PublishResume(resume.ID)
+		switch {
+		case errors.Is(err, headhunter.ErrPublishCooldown):
+			log.Info("resume is still on cooldown", zap.Error(err))
+		case err != nil:
+			log.Error("publishing resume", zap.Error(err))
+		default:
+			log.Info("resume raised in search")
+		}
+	}
+
+	return earliest, nil
+}
+
+// findResumes returns resumes matching the given ids or titles. Without them all published resumes are returned.
+func findResumes(resumes *headhunter.Resumes, args []string) ([]*headhunter.Resume, error) {
+	if len(args) == 0 {
+		var published []*headhunter.Resume
+		for _, resume := range resumes.Items {
+			if resume.IsPublished() {
+				published = append(published, resume)
+			}
+		}
+
+		if len(published) == 0 {
+			return nil, errors.New("no published resumes found")
+		}
+
+		return published, nil
+	}
+
+	found := make([]*headhunter.Resume, 0, len(args))
+	for _, arg := range args {
+		resume := resumes.Find(arg)
+		if resume == nil {
+			return nil, fmt.Errorf("resume %s not found", arg)
+		}
+		found = append(found, resume)
+	}
+
+	return found, nil
+}
+
+func resumesAdvise(cmd *cobra.Command, idOrTitle string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger, config := setup()
+	account := singleAccount(cmd, logger, config)
+
+	hh, err := openClient(ctx, logger.With(zap.String("account", account.Name)), config, account)
+	if err != nil {
+		logger.Fatal("opening account", zap.String("account", account.Name), zap.Error(err))
+	}
+
+	advisor, err := newAIAdvisor(ctx, config.AI, config.API.language(), logger)
+	if err != nil {
+		logger.Fatal("building ai advisor", zap.Error(err))
+	}
+
+	resume, raw := mustGetResume(logger, hh, idOrTitle)
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	ids, err := adviseVacancyIDs(cmd, hh, config, account, logger)
+	if err != nil {
+		logger.Fatal("choosing vacancies", zap.Error(err))
+	}
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	// Search results have no key skills, so every vacancy is fetched in full.
+	vacancies := make([]*headhunter.Vacancy, 0, len(ids))
+	for _, id := range ids {
+		vacancy, err := hh.GetVacancy(id)
+		if err != nil {
+			logger.Warn("skipping vacancy", zap.String("vacancy_id", id), zap.Error(err))
+			continue
+		}
+		vacancies = append(vacancies, vacancy)
+	}
+
+	demand := ai.NewMarketDemand(vacancies)
+	logger.Info("analysing resume against vacancies", zap.String("resume_title", resume.Title), zap.Int("vacancies", demand.Vacancies))
+
+	advice, err := advisor.Advise(ctx, raw, demand)
+	if err != nil {
+		logger.Fatal("getting advice", zap.Error(err))
+	}
+
+	printAdvice(os.Stdout, demand, advice)
+}
+
+// adviseVacancyIDs returns ids of vacancies approved in the run result or found by the configured search.
+func adviseVacancyIDs(cmd *cobra.Command, hh *headhunter.Client, config *Config, account *AccountConfig, logger *zap.Logger) ([]string, error) {
+	path := cmd.Flag("from-result").Value.String()
+	if path == "" {
+		if config.Search == nil {
+			return nil, errors.New("search section is required to find vacancies, or pass --from-result")
+		}
+
+		if err := resolveSearch(hh, config, logger); err != nil {
+			return nil, fmt.Errorf("resolving search parameters: %w", err)
+		}
+
+		vacancies, err := getVacancies(hh, config.Search, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		return headhunter.IDs(vacancies.Items), nil
+	}
+
+	run, err := report.ReadRunFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range run.Accounts {
+		if result.Account == account.Name {
+			return result.VacancyIDs(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("account %s not found in %s", account.Name, path)
+}
+
+func printAdvice(w io.Writer, demand *ai.MarketDemand, advice *ai.ResumeAdvice) {
+	fmt.Fprintf(w, "Based on %d vacancies.\n", demand.Vacancies)
+
+	if len(demand.Skills) > 0 {
+		top := make([]string, 0, maxAdviseSkills)
+		for _, skill := range demand.Skills[:min(len(demand.Skills), maxAdviseSkills)] {
+			top = append(top, fmt.Sprintf("%s (%d)", skill.Name, skill.Count))
+		}
+		fmt.Fprintf(w, "Most requested skills: %s\n", strings.Join(top, ", "))
+	}
+
+	if advice.Summary != "" {
+		fmt.Fprintf(w, "\n%s\n", advice.Summary)
+	}
+
+	for _, section := range []struct {
+		title string
+		items []string
+	}{
+		{"Missing skills", advice.MissingSkills},
+		{"Wording suggestions", advice.Wording},
+		{"Title suggestions", advice.Titles},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n%s:\n", section.title)
+		for _, item := range section.items {
+			fmt.Fprintf(w, "- %s\n", item)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"log"
+	"time"
 
 	"github.com/spigell/hh-responder/internal/headhunter"
 
@@ -17,15 +18,66 @@ type Config struct {
 	Search      *headhunter.SearchParams `mapstructure:"search"`
 	ExcludeFile string                   `mapstructure:"exclude-file"`
 	UserAgent   string                   `mapstructure:"user-agent"`
+	API         *APIConfig               `mapstructure:"api"`
+	HTTP        *HTTPConfig              `mapstructure:"http"`
 	TokenFile   string                   `mapstructure:"token-file"`
+	OAuth       *OAuthConfig             `mapstructure:"oauth"`
 	Apply       *struct {
-		Resume  string
+		Resume string
+		// Resumes are several resume titles. The AI filter picks the best one per vacancy.
+		Resumes []string
 		Message string
 		Exclude *struct {
 			Employers []string
 		}
+		// Favorites are employers applied to first when the daily quota does not allow all vacancies.
+		Favorites *struct {
+			Employers []string
+		}
 	}
-	AI *AIConfig `mapstructure:"ai"`
+	AI        *AIConfig        `mapstructure:"ai"`
+	Telegram  *TelegramConfig  `mapstructure:"telegram"`
+	RateLimit *RateLimitConfig `mapstructure:"rate-limit"`
+	// Incremental limits searches to vacancies published since the last successful run.
+	Incremental *IncrementalConfig `mapstructure:"incremental"`
+	// Quota tracks applications per account in 24 hours to stay within the hh.ru limit.
+	Quota *QuotaConfig `mapstructure:"quota"`
+	// Queue makes runs enqueue applications for the paced worker instead of sending them at once.
+	Queue *QueueConfig `mapstructure:"queue"`
+	// Dashboard configures the web dashboard of the serve command.
+	Dashboard *DashboardConfig `mapstructure:"dashboard"`
+	// Accounts replace the top-level token, resume and exclude file when set.
+	Accounts []*AccountConfig `mapstructure:"accounts"`
+}
+
+// APIConfig points the client to another HeadHunter site or a test server.
+type APIConfig struct {
+	URL    string `mapstructure:"url"`
+	Host   string `mapstructure:"host"`
+	Locale string `mapstructure:"locale"`
+}
+
+// HTTPConfig tunes the connections to hh.ru.
+type HTTPConfig struct {
+	Proxy   string        `mapstructure:"proxy"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	CAFile  string        `mapstructure:"ca-file"`
+	// TraceFile receives every request and response with credentials redacted.
+	TraceFile string `mapstructure:"trace-file"`
+}
+
+type OAuthConfig struct {
+	ClientID         string `mapstructure:"client-id"`
+	ClientSecretFile string `mapstructure:"client-secret-file"`
+	TokenStore       string `mapstructure:"token-store"`
+	RedirectURL      string `mapstructure:"redirect-url"`
+}
+
+type TelegramConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	TokenFile   string `mapstructure:"token-file"`
+	ChatID      int64  `mapstructure:"chat-id"`
+	PollTimeout int    `mapstructure:"poll-timeout"`
 }
 
 type AIConfig struct {
@@ -33,6 +85,13 @@ type AIConfig struct {
 	Provider        string        `mapstructure:"provider"`
 	MinimumFitScore float64       `mapstructure:"minimum-fit-score"`
 	Gemini          *GeminiConfig `mapstructure:"gemini"`
+	Fake            *FakeAIConfig `mapstructure:"fake"`
+}
+
+// FakeAIConfig configures the provider answering with scripted or replayed assessments.
+type FakeAIConfig struct {
+	ScriptFile string `mapstructure:"script-file"`
+	ReplayFile string `mapstructure:"replay-file"`
 }
 
 type GeminiConfig struct {
@@ -76,19 +135,33 @@ func init() {
 		log.Fatalf("binding GEMINI_API_KEY_FILE environment variable: %v", err)
 	}
 
+	if err := viper.BindEnv("telegram.token-file", "TELEGRAM_BOT_TOKEN_FILE"); err != nil {
+		log.Fatalf("binding TELEGRAM_BOT_TOKEN_FILE environment variable: %v", err)
+	}
+
+	if err := viper.BindEnv("oauth.client-secret-file", "HH_CLIENT_SECRET_FILE"); err != nil {
+		log.Fatalf("binding HH_CLIENT_SECRET_FILE environment variable: %v", err)
+	}
+
+	if err := viper.BindEnv("dashboard.token-file", "HH_DASHBOARD_TOKEN_FILE"); err != nil {
+		log.Fatalf("binding HH_DASHBOARD_TOKEN_FILE environment variable: %v", err)
+	}
+
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "a config file (default is hh-responder.yaml in current directory)")
 	rootCmd.PersistentFlags().BoolP("debug", "d", false, "verbose/debug output")
 	rootCmd.PersistentFlags().BoolP("json", "j", false, "json format for logging")
+	rootCmd.PersistentFlags().String("trace-http", "", "append hh.ru requests and responses to the file, credentials are redacted")
 
 	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
 	viper.BindPFlag("json", rootCmd.PersistentFlags().Lookup("json"))
+	viper.BindPFlag("http.trace-file", rootCmd.PersistentFlags().Lookup("trace-http"))
 }
 
 func initConfig() {
-	// Config needed only for run command now. If there is no config, we can skip initialization
-	if runCmd.CalledAs() == "" {
+	// Config is needed only for commands working with the hh.ru account. If there is no config, we can skip initialization
+	if !needsConfig() {
 		return
 	}
 
@@ -105,6 +178,16 @@ func initConfig() {
 	}
 }
 
+func needsConfig() bool {
+	for _, cmd := range []*cobra.Command{runCmd, serveCmd, authLoginCmd, resumesListCmd, resumesShowCmd, resumesPublishCmd, resumesTouchCmd, resumesAdviseCmd, searchExploreCmd, dictSearchCmd, queueListCmd, queueDropCmd, queueFlushCmd, queueWorkCmd} {
+		if cmd.CalledAs() != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func getConfig() (*Config, error) {
 	var config *Config
 	err := viper.Unmarshal(&config)
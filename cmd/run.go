@@ -5,20 +5,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/spigell/hh-responder/internal/ai"
+	"github.com/spigell/hh-responder/internal/ai/fake"
 	"github.com/spigell/hh-responder/internal/ai/gemini"
 	"github.com/spigell/hh-responder/internal/filtering"
 	"github.com/spigell/hh-responder/internal/headhunter"
-	"github.com/spigell/hh-responder/internal/logger"
+	"github.com/spigell/hh-responder/internal/oauth"
+	"github.com/spigell/hh-responder/internal/report"
 	"github.com/spigell/hh-responder/internal/secrets"
+	"github.com/spigell/hh-responder/internal/telegram"
+	"github.com/spigell/hh-responder/internal/tui"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 const (
@@ -27,21 +32,31 @@ const (
 	PromptBack                = "back"
 	PromptReportByEmployers   = "Report by employers"
 	PromptManualApply         = "Apply vacancies in manual mode"
+	PromptReview              = "Review vacancies in full-screen mode"
 	PromptAppendToExcludeFile = "Append all vacancies to exclude file"
 	PromptVacanciesToFile     = "Dump vacancies to file"
+	PromptExport              = "Export vacancies to the output file"
 	defaultFallbackMessage    = "Hello! I would like to apply for this vacancy."
 )
 
-var errExit = errors.New("exit requested")
+var (
+	errExit              = errors.New("exit requested")
+	errApplicationBudget = errors.New("application budget of the account is exhausted")
+)
 
 var prompt = promptui.Select{
 	Label: "Procced?",
-	Items: []string{PromptYes, PromptNo, PromptReportByEmployers, PromptManualApply, PromptVacanciesToFile},
+	Items: []string{PromptYes, PromptNo, PromptReportByEmployers, PromptManualApply, PromptReview, PromptVacanciesToFile, PromptExport},
 }
 
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run the hh-responder main command",
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		// Bind here since other commands bind the same keys to their own flags.
+		viper.BindPFlag("exclude-file", cmd.Flags().Lookup("exclude-file"))
+		viper.BindPFlag("result-file", cmd.Flags().Lookup("result-file"))
+	},
 	Run: func(cmd *cobra.Command, _ []string) {
 		run(cmd)
 	},
@@ -53,123 +68,150 @@ func init() {
 	runCmd.Flags().BoolP("do-not-exclude-applied", "f", false, "do not exclude vacancies if already applied")
 	runCmd.Flags().BoolP("auto-aprove", "y", false, "do not ask for confirmation if found suitable vacancies")
 	runCmd.Flags().StringP("exclude-file", "e", "", "special file with vacancies to exclude. Default is unset.")
-
-	viper.BindPFlag("exclude-file", runCmd.Flags().Lookup("exclude-file"))
+	runCmd.Flags().Bool("telegram", false, "review vacancies with the Telegram bot instead of the terminal prompt")
+	runCmd.Flags().StringP("output", "o", "", "file to export filtered vacancies to. With --auto-aprove the export is done before applying")
+	runCmd.Flags().String("format", "", "export format: csv, markdown or html. Guessed by the output file extension when unset")
+	runCmd.Flags().String("account", "", "process only the account with the given name")
+	runCmd.Flags().Bool("full-scan", false, "search the whole period even when incremental search is enabled")
+	runCmd.Flags().String("result-file", "", "write a JSON summary of the run to the file. Use - for stdout, logs are moved to stderr then")
+
+	viper.BindPFlag("telegram.enabled", runCmd.Flags().Lookup("telegram"))
 }
 
-// run is the main command for the cli.
+// run is the main command for the cli. Accounts are processed one by one and a failure of one
+// does not stop the others.
 func run(cmd *cobra.Command) {
-	ctx := context.Background()
+	logger, config := setup()
+	requireApply(logger, config)
+	summary := report.NewRun(version)
 
-	logger, err := logger.New(viper.GetBool("json"), viper.GetBool("debug"))
-	if err != nil {
-		log.Fatalf("creating a logger: %s", err)
-	}
+	selected, err := selectAccounts(config, cmd.Flag("account").Value.String())
 
-	config, err := getConfig()
-	if err != nil {
-		logger.Fatal("getting a config", zap.Error(err))
+	var failed []string
+	for _, account := range selected {
+		if accountErr := runAccount(cmd, logger, config, account, len(selected) > 1, summary); accountErr != nil {
+			logger.Error("account failed", zap.String("account", account.Name), zap.Error(accountErr))
+			failed = append(failed, account.Name)
+		}
 	}
 
-	logger.Info("starting the hh-responder", zap.String("version", version))
-
-	// do not bother error since there is a valid parseable config
-	pretty, _ := json.MarshalIndent(config, "", "  ")
-	logger.Debug(fmt.Sprintf("starting with config: \n %s", pretty))
+	if err == nil && len(failed) > 0 {
+		err = fmt.Errorf("failed accounts: %s", strings.Join(failed, ", "))
+	}
 
-	if config == nil {
-		logger.Fatal("config is required")
+	if resultFile := viper.GetString("result-file"); resultFile != "" {
+		summary.Finish(err)
+		if writeErr := summary.WriteFile(resultFile); writeErr != nil {
+			logger.Error("writing result file", zap.Error(writeErr), zap.String("filename", resultFile))
+		}
 	}
 
-	if config.Apply == nil || config.Apply.Resume == "" {
-		logger.Fatal("resume title is required under apply.resume to evaluate and apply to vacancies")
+	if err != nil {
+		logger.Fatal("exiting", zap.Error(err))
 	}
+}
+
+// runAccount processes the account and records the outcome in the summary.
+func runAccount(cmd *cobra.Command, logger *zap.Logger, config *Config, account *AccountConfig, multiAccount bool, summary *report.Run) error {
+	result := summary.AddAccount(account.Name, config.Search)
 
-	token, err := resolveToken(config)
+	s, err := openSession(context.Background(), logger, config, account)
 	if err != nil {
-		logger.Fatal(
-			"loading headhunter token",
-			zap.Error(err),
-			zap.String("hint", "set HH_TOKEN_FILE environment variable or the 'token-file' key in the configuration file"),
-		)
+		result.Finish(err)
+		return err
 	}
 
-	hh := headhunter.New(ctx, token, logger)
+	s.multiAccount = multiAccount
+	s.result = result
+	s.result.Resumes = s.resumes
 
-	if config.UserAgent != "" {
-		hh.UserAgent = config.UserAgent
+	err = process(cmd, s)
+	if errors.Is(err, errExit) {
+		err = nil
 	}
 
-	resumes, err := hh.GetMineResumes()
-	if err != nil {
-		logger.Fatal("getting mine resumes", zap.Error(err))
+	if err == nil {
+		s.commitSearch()
 	}
 
-	logger.Info("getting mine resumes", zap.Int("count", resumes.Len()))
+	result.Finish(err)
 
-	selectedResume := resumes.FindByTitle(config.Apply.Resume)
-	if selectedResume == nil {
-		logger.Fatal("resume with given title not found",
-			zap.Any("existed resumes titles", resumes.Titles()),
-			zap.String("resume title", config.Apply.Resume),
-		)
-	}
+	s.logger.Info("account finished",
+		zap.Int("found", result.Found),
+		zap.Int("kept", len(result.Kept)),
+		zap.Int("dropped", len(result.Dropped)),
+		zap.Int("applications", len(result.Applications)),
+	)
 
-	logger.Info("starting the search", zap.String("search", config.Search.Text))
+	return err
+}
 
-	vacancies, err := getVacancies(hh, config, logger)
+// process collects vacancies and passes them to the chosen way of review.
+func process(cmd *cobra.Command, s *session) error {
+	vacancies, err := s.collect(cmd)
 	if err != nil {
-		logger.Fatal("getting available vacancies", zap.Error(err))
+		return fmt.Errorf("collecting vacancies: %w", err)
 	}
 
+	s.fitQuota(vacancies)
+
 	if vacancies.Len() == 0 {
-		logger.Info("exiting", zap.String("reason", "no vacancies found"))
-		return
+		s.logger.Info("exiting", zap.String("reason", "no vacancies left after filters"))
+		return nil
 	}
 
-	filters := prepareFilters(ctx, cmd, hh, config, selectedResume, logger)
+	autoApprove := cmd.Flag("auto-aprove").Value.String() == "true"
 
-	filtered, err := filters.RunFilters(ctx, vacancies)
-	if err != nil {
-		logger.Fatal("filtering failed", zap.Error(err))
+	if viper.GetBool("telegram.enabled") && !autoApprove {
+		if err := telegramReview(s, vacancies); err != nil {
+			return fmt.Errorf("reviewing vacancies in telegram: %w", err)
+		}
+		return nil
 	}
-	vacancies = filtered
 
-	if vacancies.Len() == 0 {
-		logger.Info("exiting", zap.String("reason", "no vacancies left after filters"))
-		return
+	if autoApprove && cmd.Flag("output").Value.String() != "" {
+		if err := export(cmd, s, vacancies); err != nil {
+			return fmt.Errorf("exporting vacancies: %w", err)
+		}
 	}
 
 	action := PromptYes
 	for {
-		var err error
-		if cmd.Flag("auto-aprove").Value.String() == "false" {
+		if !autoApprove {
 			_, action, err = prompt.Run()
 			if err != nil {
-				logger.Fatal("exiting", zap.Error(err))
+				return err
 			}
 		}
 
-		logger.Info("current list of vacancies", zap.Int("count", vacancies.Len()))
+		s.logger.Info("current list of vacancies", zap.Int("count", vacancies.Len()))
 
-		if err := handleAction(action, hh, logger, config, vacancies, selectedResume); err != nil {
-			if errors.Is(err, errExit) {
-				return
+		if err := handleAction(cmd, action, s, vacancies); err != nil {
+			// Running out of the budget is logged by apply and ends the run normally.
+			if outOfBudget(err) {
+				return nil
 			}
-			logger.Fatal("exiting", zap.Error(err))
+			return err
 		}
 	}
 }
 
-func handleAction(action string, hh *headhunter.Client, logger *zap.Logger, config *Config, vacancies *headhunter.Vacancies, resume *headhunter.Resume) error {
+func handleAction(cmd *cobra.Command, action string, s *session, vacancies *headhunter.Vacancies) error {
+	logger := s.logger
+
 	switch action {
 	case PromptYes:
-		return apply(hh, *logger, resume, vacancies, config.Apply.Message)
+		if err := s.apply(vacancies); err != nil {
+			return err
+		}
+		return errExit
 	case PromptNo:
 		logger.Info("exiting", zap.String("reason", "got no from prompt"))
 		return errExit
 	case PromptManualApply:
-		return manualApply(hh, logger, config, vacancies, resume)
+		return manualApply(s, vacancies)
+	case PromptReview:
+		return review(s, vacancies)
 	case PromptReportByEmployers:
 		pretty, _ := json.MarshalIndent(vacancies.ReportByEmployer(), "", "  ")
 		logger.Info(string(pretty), zap.Int("vacancies count", vacancies.Len()))
@@ -181,32 +223,65 @@ func handleAction(action string, hh *headhunter.Client, logger *zap.Logger, conf
 		}
 		logger.Info("dumping result to file", zap.String("filename", filename))
 		return nil
+	case PromptExport:
+		return export(cmd, s, vacancies)
 	default:
 		return fmt.Errorf("invalid action: %s", action)
 	}
 }
 
-func resolveToken(config *Config) (string, error) {
-	if config == nil {
-		return "", errors.New("config is required")
+// export writes vacancies to the file set by the output flag.
+func export(cmd *cobra.Command, s *session, vacancies *headhunter.Vacancies) error {
+	output := s.accountPath(cmd.Flag("output").Value.String())
+	if output == "" {
+		return errors.New("output file is not set (use --output)")
 	}
 
-	tokenFile := strings.TrimSpace(config.TokenFile)
-	if tokenFile == "" {
-		tokenFile = strings.TrimSpace(viper.GetString("token-file"))
+	format, err := report.ParseFormat(cmd.Flag("format").Value.String(), output)
+	if err != nil {
+		return err
 	}
 
+	if err := report.WriteFile(output, format, vacancies, s.dropped); err != nil {
+		return fmt.Errorf("export vacancies: %w", err)
+	}
+
+	s.logger.Info("vacancies exported",
+		zap.String("filename", output),
+		zap.String("format", string(format)),
+		zap.Int("count", vacancies.Len()),
+		zap.Int("dropped", len(s.dropped)),
+	)
+
+	return nil
+}
+
+// resolveToken returns the hh.ru access token of the account. Tokens from the OAuth store come with a refresher.
+func resolveToken(ctx context.Context, config *Config, account *AccountConfig, logger *zap.Logger) (string, *oauth.Refresher, error) {
+	if account == nil {
+		return "", nil, errors.New("account is required")
+	}
+
+	if account.OAuth != nil && strings.TrimSpace(account.OAuth.TokenStore) != "" {
+		return oauthToken(ctx, account.OAuth, config.API, config.HTTP, logger)
+	}
+
+	tokenFile := strings.TrimSpace(account.TokenFile)
 	if tokenFile == "" {
-		return "", errors.New("headhunter token file is not configured")
+		return "", nil, errors.New("headhunter token file is not configured")
 	}
 
-	return secrets.Load(secrets.Source{
+	token, err := secrets.Load(secrets.Source{
 		Name: "headhunter token",
 		File: tokenFile,
 	})
+
+	return token, nil, err
 }
 
-func manualApply(hh *headhunter.Client, logger *zap.Logger, config *Config, vacancies *headhunter.Vacancies, resume *headhunter.Resume) error {
+func manualApply(s *session, vacancies *headhunter.Vacancies) error {
+	logger := s.logger
+
 	for {
 		items := make([]string, 0)
 		v := make([]*headhunter.Vacancy, 0)
@@ -219,7 +294,7 @@ func manualApply(hh *headhunter.Client, logger *zap.Logger, config *Config, vaca
 			items = append(items, label)
 		}
 
-		excludeFile := viper.GetString("exclude-file")
+		excludeFile := s.excludeFile
 		if excludeFile != "" && vacancies.Len() != 0 {
 			items = append(items, PromptAppendToExcludeFile)
 		}
@@ -238,17 +313,11 @@ func manualApply(hh *headhunter.Client, logger *zap.Logger, config *Config, vaca
 		case PromptBack:
 			return nil
 		case PromptAppendToExcludeFile:
-			excluded, err := headhunter.GetExludedVacanciesFromFile(excludeFile)
+			excluded, err := appendToExcludeFile(excludeFile, vacancies, headhunter.ExcludeReasonManualApply)
 			if err != nil {
 				return err
 			}
 
-			excluded.Append(vacancies.ToExcluded(headhunter.ExcludeActorHuman, headhunter.ExcludeReasonManualApply))
-
-			if err = excluded.ToFile(excludeFile); err != nil {
-				return err
-			}
-
 			logger.Info("appended to exlude file", zap.String("filename", excludeFile))
 
 			vacancies.Exclude(headhunter.VacancyIDField, excluded.VacanciesIDs())
@@ -261,7 +330,7 @@ func manualApply(hh *headhunter.Client, logger *zap.Logger, config *Config, vaca
 				return fmt.Errorf("there is no such vacancy id %s", vacancyID)
 			}
 
-			if err = apply(hh, *logger, resume, &headhunter.Vacancies{Items: v}, config.Apply.Message); err != nil {
+			if err = s.apply(&headhunter.Vacancies{Items: v}); err != nil {
 				return err
 			}
 
@@ -270,65 +339,232 @@ func manualApply(hh *headhunter.Client, logger *zap.Logger, config *Config, vaca
 	}
 }
 
-func apply(hh *headhunter.Client, logger zap.Logger, resume *headhunter.Resume, vacancies *headhunter.Vacancies, defaultMessage string) error {
-	for _, vacancy := range vacancies.Items {
-		message := vacancy.AI.Message
-		if message == "" {
-			message = defaultMessage
+// appendToExcludeFile appends vacancies excluded by a human to the exclude file and returns its new content.
+func appendToExcludeFile(excludeFile string, vacancies *headhunter.Vacancies, reason string) (*headhunter.ExcludedVacancies, error) {
+	return headhunter.UpdateExcludedFile(excludeFile, func(excluded *headhunter.ExcludedVacancies) error {
+		excluded.Append(vacancies.ToExcluded(headhunter.ExcludeActorHuman, reason))
+		return nil
+	})
+}
+
+func telegramReview(s *session, vacancies *headhunter.Vacancies) error {
+	logger, config := s.logger, s.config
+
+	if config.Telegram == nil || config.Telegram.ChatID == 0 {
+		return errors.New("telegram.chat-id is required for the telegram mode")
+	}
+
+	token, err := secrets.Load(secrets.Source{
+		Name: "telegram bot token",
+		File: config.Telegram.TokenFile,
+	})
+	if err != nil {
+		return fmt.Errorf("%w (set telegram.token-file or TELEGRAM_BOT_TOKEN_FILE)", err)
+	}
+
+	// Telegram requests are not traced: the bot token is a part of their urls.
+	rt, err := config.HTTP.transport(false)
+	if err != nil {
+		return err
+	}
+
+	botLogger := logger.With(zap.String("frontend", "telegram"))
+	client := telegram.New(token, botLogger)
+	client.HTTPClient.Transport = rt
+
+	bot := telegram.NewBot(client, config.Telegram.ChatID, config.Telegram.PollTimeout, botLogger)
+
+	excludeFile := s.excludeFile
+
+	return bot.Review(s.ctx, vacancies, func(_ context.Context, decision telegram.Decision, vacancy *headhunter.Vacancy) error {
+		single := &headhunter.Vacancies{Items: []*headhunter.Vacancy{vacancy}}
+
+		switch decision {
+		case telegram.DecisionApply:
+			return s.apply(single)
+		case telegram.DecisionExclude:
+			if excludeFile == "" {
+				return errors.New("exclude file is not configured")
+			}
+			if _, err := appendToExcludeFile(excludeFile, single, headhunter.ExcludeReasonTelegram); err != nil {
+				return err
+			}
+			logger.Info("appended to exlude file", zap.String("filename", excludeFile), zap.String("vacancy_id", vacancy.ID))
+			return nil
+		default:
+			return nil
 		}
+	})
+}
 
-		if message == "" {
-			message = defaultFallbackMessage
-			logger.Warn("falling back to default built-in message",
-				zap.String("vacancy_id", vacancy.ID),
-				zap.String("hint", "specify message in apply section"),
-			)
+// review shows the full-screen review. Logs are written after the screen is closed
+// to keep it intact.
+func review(s *session, vacancies *headhunter.Vacancies) error {
+	logger := s.logger
+	core, deferred := observer.New(zapcore.DebugLevel)
+	s.logger = zap.New(core)
+
+	result, err := tui.Run(vacancies, s.config.Apply.Message, reviewActions(s))
+
+	s.logger = logger
+	for _, entry := range deferred.AllUntimed() {
+		if checked := logger.Check(entry.Level, entry.Message); checked != nil {
+			checked.Write(entry.Context...)
 		}
+	}
 
-		if err := hh.ApplyWithMessage(resume, vacancy, message); err != nil {
+	vacancies.Exclude(headhunter.VacancyIDField, result.Applied)
+	vacancies.Exclude(headhunter.VacancyIDField, result.Excluded)
+
+	s.logger.Info("review finished",
+		zap.Strings("applied_vacancies", result.Applied),
+		zap.Strings("excluded_vacancies", result.Excluded),
+		zap.String("exclude_file", s.excludeFile),
+	)
+
+	return err
+}
+
+// reviewActions are the actions of the full-screen review. Applications go the way of apply.
+func reviewActions(s *session) tui.Actions {
+	return tui.Actions{
+		Apply: s.applyEdited,
+		Exclude: func(vacancy *headhunter.Vacancy, reason string) error {
+			if s.excludeFile == "" {
+				return errors.New("exclude file is not configured")
+			}
+
+			single := &headhunter.Vacancies{Items: []*headhunter.Vacancy{vacancy}}
+			_, err := appendToExcludeFile(s.excludeFile, single, reason)
+			return err
+		},
+		Details: func(vacancy *headhunter.Vacancy) (*headhunter.Vacancy, error) {
+			return s.hh.GetVacancy(vacancy.ID)
+		},
+	}
+}
+
+// apply sends applications using AI drafted messages or the configured default one.
+// With the queue enabled they are enqueued for the worker instead. When the budget runs out
+// it stops with errApplicationBudget or errDailyQuota.
+func (s *session) apply(vacancies *headhunter.Vacancies) error {
+	return s.applyMessages(vacancies, s.messageFor)
+}
+
+// applyEdited applies to the vacancy with the message edited by a human like apply does.
+// An empty message means the drafted or the configured one.
+func (s *session) applyEdited(vacancy *headhunter.Vacancy, message string) error {
+	single := &headhunter.Vacancies{Items: []*headhunter.Vacancy{vacancy}}
+	if strings.TrimSpace(message) == "" {
+		return s.apply(single)
+	}
+
+	return s.applyMessages(single, func(*headhunter.Vacancy) string { return message })
+}
+
+// applyMessages sends or enqueues applications with the messages returned for the vacancies.
+func (s *session) applyMessages(vacancies *headhunter.Vacancies, messageFor func(*headhunter.Vacancy) string) error {
+	if cfg := s.config.Queue; cfg != nil && cfg.Enabled {
+		return s.enqueue(vacancies, messageFor)
+	}
+
+	for i, vacancy := range vacancies.Items {
+		err := s.applyWithMessage(vacancy, messageFor(vacancy))
+		// With the quota enabled the rest is offered in the next run. The error is returned to let
+		// interactive callers know nothing was sent, the batch run stops normally on it.
+		if outOfBudget(err) {
+			s.logger.Warn("stopped applying",
+				zap.Error(err),
+				zap.Int("applied", i),
+				zap.Int("left", vacancies.Len()-i),
+				zap.Bool("carried_over", s.quota != nil),
+			)
+			s.carryOver(vacancies.Items[i:])
+			if s.quota == nil {
+				// Nothing keeps the rest, the next run must find it again.
+				s.discardSearch("vacancies left after the application budget")
+			}
+			return err
+		}
+		if err != nil {
 			return err
 		}
 
-		logger.Info("successfully applied to vacancy",
+		s.logger.Info("successfully applied to vacancy",
 			zap.String("vacancy_id", vacancy.ID),
 			zap.String("vacancy_name", vacancy.Name),
 		)
 	}
 
-	logger.Info("successfully applied to vacancies", zap.Int("count", vacancies.Len()))
+	s.logger.Info("successfully applied to vacancies", zap.Int("count", vacancies.Len()))
 	return nil
 }
 
-func newAIMatcher(ctx context.Context, cfg *AIConfig, logger *zap.Logger) (ai.Matcher, error) {
-	provider := strings.TrimSpace(strings.ToLower(cfg.Provider))
-	if provider != "" && provider != "gemini" {
-		return nil, fmt.Errorf("unsupported ai provider: %s", cfg.Provider)
+// outOfBudget reports errors of the account running out of its per-run or daily budget of applications.
+func outOfBudget(err error) bool {
+	return errors.Is(err, errApplicationBudget) || errors.Is(err, errDailyQuota)
+}
+
+// messageFor returns the AI drafted message for the vacancy or the configured default one.
+func (s *session) messageFor(vacancy *headhunter.Vacancy) string {
+	message := s.config.Apply.Message
+	if vacancy.AI != nil && vacancy.AI.Message != "" {
+		message = vacancy.AI.Message
 	}
 
-	apiKey, err := secrets.Load(secrets.Source{
-		Name: "gemini api key",
-		File: cfg.Gemini.APIKeyFile,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("%w (set ai.gemini.api-key-file or GEMINI_API_KEY_FILE)", err)
+	if message == "" {
+		message = defaultFallbackMessage
+		s.logger.Warn("falling back to default built-in message",
+			zap.String("vacancy_id", vacancy.ID),
+			zap.String("hint", "specify message in apply section"),
+		)
 	}
 
-	genLogger := logger.With(
-		zap.String("provider", "gemini"),
-		zap.String("model", cfg.Gemini.Model),
-		zap.Int("ai_retry_attempts", cfg.Gemini.MaxRetries),
-	)
+	return message
+}
 
-	generator, err := gemini.NewGenerator(ctx, apiKey, cfg.Gemini.Model, cfg.Gemini.MaxRetries, genLogger)
-	if err != nil {
-		return nil, err
+// applyWithMessage sends a single application with the resume fitting the vacancy best.
+func (s *session) applyWithMessage(vacancy *headhunter.Vacancy, message string) error {
+	return s.applyWithResume(vacancy, s.resumeFor(vacancy), message)
+}
+
+// applyWithResume sends a single application and records its outcome.
+func (s *session) applyWithResume(vacancy *headhunter.Vacancy, resume *headhunter.Resume, message string) error {
+	if limit := s.account.RateLimit; limit != nil && limit.MaxApplications > 0 && s.applied >= limit.MaxApplications {
+		return fmt.Errorf("%w: %d applications", errApplicationBudget, limit.MaxApplications)
+	}
+	if err := s.checkQuota(); err != nil {
+		return err
+	}
+
+	err := s.hh.ApplyWithMessage(resume, vacancy, message)
+	if err == nil {
+		s.applied++
+	}
+	err = s.recordApplication(err)
+
+	if s.result != nil {
+		s.result.AddApplication(vacancy, resume, err)
 	}
 
+	return err
+}
+
+func newAIMatcher(ctx context.Context, cfg *AIConfig, language string, logger *zap.Logger) (ai.Matcher, error) {
 	minScore := cfg.MinimumFitScore
 	if minScore < 0 {
 		minScore = 0
 	}
 
+	if cfg.provider() == ai.ProviderFake {
+		return newFakeMatcher(cfg, minScore, logger)
+	}
+
+	generator, err := newAIGenerator(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	matcherLogger := logger.With(
 		zap.String("provider", "gemini"),
 		zap.String("model", cfg.Gemini.Model),
@@ -336,6 +572,7 @@ func newAIMatcher(ctx context.Context, cfg *AIConfig, logger *zap.Logger) (ai.Ma
 	)
 
 	matcher := gemini.NewMatcher(generator, minScore, cfg.Gemini.MaxLogLength, matcherLogger)
+	matcher.SetLanguage(language)
 	if cfg.Gemini.PromptOverrides != nil {
 		matcher.SetPromptOverrides(gemini.PromptOverrides{
 			ExtraCriteria:     cfg.Gemini.PromptOverrides.ExtraCriteria,
@@ -350,9 +587,94 @@ func newAIMatcher(ctx context.Context, cfg *AIConfig, logger *zap.Logger) (ai.Ma
 	return matcher, nil
 }
 
-// getVacancies returns a list of vacancies that match the config.
-func getVacancies(hh *headhunter.Client, config *Config, logger *zap.Logger) (*headhunter.Vacancies, error) {
-	results, err := hh.Search(config.Search)
+// newFakeMatcher creates the matcher answering with the script and the replayed run result.
+func newFakeMatcher(cfg *AIConfig, minScore float64, logger *zap.Logger) (ai.Matcher, error) {
+	if cfg.Fake == nil || (cfg.Fake.ScriptFile == "" && cfg.Fake.ReplayFile == "") {
+		return nil, errors.New("ai.fake.script-file or ai.fake.replay-file is required for the fake ai provider")
+	}
+
+	script := &fake.Script{}
+	if cfg.Fake.ScriptFile != "" {
+		var err error
+		if script, err = fake.LoadScript(cfg.Fake.ScriptFile); err != nil {
+			return nil, fmt.Errorf("loading ai script: %w", err)
+		}
+	}
+
+	if cfg.Fake.ReplayFile != "" {
+		replayed, err := script.Replay(cfg.Fake.ReplayFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading responses to replay: %w", err)
+		}
+		logger.Info("replaying recorded ai responses", zap.String("filename", cfg.Fake.ReplayFile), zap.Int("count", replayed))
+	}
+
+	return fake.NewMatcher(script, minScore, logger.With(
+		zap.String("provider", ai.ProviderFake),
+		zap.Float64("minimum_fit_score", minScore),
+	)), nil
+}
+
+func newAIAdvisor(ctx context.Context, cfg *AIConfig, language string, logger *zap.Logger) (ai.Advisor, error) {
+	if cfg != nil && cfg.provider() == ai.ProviderFake {
+		return nil, errors.New("resume advice is not supported by the fake ai provider")
+	}
+
+	generator, err := newAIGenerator(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	advisorLogger := logger.With(
+		zap.String("provider", "gemini"),
+		zap.String("model", cfg.Gemini.Model),
+	)
+
+	advisor := gemini.NewAdvisor(generator, cfg.Gemini.MaxLogLength, advisorLogger)
+	advisor.SetLanguage(language)
+
+	return advisor, nil
+}
+
+// provider returns the configured AI provider. Gemini is the default one.
+func (c *AIConfig) provider() string {
+	provider := strings.TrimSpace(strings.ToLower(c.Provider))
+	if provider == "" {
+		return ai.ProviderGemini
+	}
+	return provider
+}
+
+// newAIGenerator creates the content generator of the configured provider.
+func newAIGenerator(ctx context.Context, cfg *AIConfig, logger *zap.Logger) (*gemini.Generator, error) {
+	if cfg == nil || cfg.Gemini == nil {
+		return nil, errors.New("gemini configuration is required for the ai section")
+	}
+
+	if cfg.provider() != ai.ProviderGemini {
+		return nil, fmt.Errorf("unsupported ai provider: %s", cfg.Provider)
+	}
+
+	apiKey, err := secrets.Load(secrets.Source{
+		Name: "gemini api key",
+		File: cfg.Gemini.APIKeyFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w (set ai.gemini.api-key-file or GEMINI_API_KEY_FILE)", err)
+	}
+
+	genLogger := logger.With(
+		zap.String("provider", "gemini"),
+		zap.String("model", cfg.Gemini.Model),
+		zap.Int("ai_retry_attempts", cfg.Gemini.MaxRetries),
+	)
+
+	return gemini.NewGenerator(ctx, apiKey, cfg.Gemini.Model, cfg.Gemini.MaxRetries, genLogger)
+}
+
+// getVacancies returns a list of vacancies that match the search.
+func getVacancies(hh *headhunter.Client, params *headhunter.SearchParams, logger *zap.Logger) (*headhunter.Vacancies, error) {
+	results, err := hh.Search(params)
 	if err != nil {
 		return nil, fmt.Errorf("search: %w", err)
 	}
@@ -361,8 +683,10 @@ func getVacancies(hh *headhunter.Client, config *Config, logger *zap.Logger) (*h
 	return results, nil
 }
 
-func prepareFilters(ctx context.Context, cmd *cobra.Command, hh *headhunter.Client, config *Config, resume *headhunter.Resume, logger *zap.Logger) *filtering.Filtering {
-	aiFilter, err := prepareAIFilter(ctx, hh, config.AI, resume, logger, config.ExcludeFile)
+func prepareFilters(cmd *cobra.Command, s *session) *filtering.Filtering {
+	hh, config, logger := s.hh, s.config, s.logger
+
+	aiFilter, err := prepareAIFilter(s.ctx, hh, config.AI, config.API.language(), s.resumes, logger, s.excludeFile)
 	if err != nil {
 		logger.Warn("skipping AI filter", zap.Error(err))
 		aiFilter.Disable("skipping by error")
@@ -372,7 +696,7 @@ func prepareFilters(ctx context.Context, cmd *cobra.Command, hh *headhunter.Clie
 		filtering.NewWithTest(),
 		prepareAppliedHistoryFilter(cmd, hh, logger),
 		filtering.NewExludedEmployers(config.Apply.Exclude.Employers),
-		filtering.NewExcludeFile(config.ExcludeFile),
+		filtering.NewExcludeFile(s.excludeFile),
 		aiFilter,
 	}
 
@@ -401,7 +725,7 @@ func prepareAppliedHistoryFilter(cmd *cobra.Command, client *headhunter.Client,
 	return filtering.NewAppliedHistory(cfg, deps)
 }
 
-func prepareAIFilter(ctx context.Context, client *headhunter.Client, config *AIConfig, resume *headhunter.Resume, logger *zap.Logger, excludeFile string) (filtering.Filter, error) {
+func prepareAIFilter(ctx context.Context, client *headhunter.Client, config *AIConfig, language string, resumes []*headhunter.Resume, logger *zap.Logger, excludeFile string) (filtering.Filter, error) {
 	disabled := filtering.NewAIFit(&filtering.AIFitFilterConfig{
 		Enabled: false,
 	}, nil)
@@ -410,22 +734,23 @@ func prepareAIFilter(ctx context.Context, client *headhunter.Client, config *AIC
 		return disabled, nil
 	}
 
-	if config.Gemini == nil {
-		return disabled, fmt.Errorf("gemini configuration is required when ai filter is enabled")
-	}
-
 	aiConfig := &filtering.AIFitFilterConfig{
 		Enabled:         config.Enabled,
-		Provider:        config.Provider,
+		Provider:        config.provider(),
 		MinimumFitScore: config.MinimumFitScore,
-		Gemini: &filtering.AIGeminiConfig{
+	}
+
+	if config.Gemini != nil {
+		aiConfig.Gemini = &filtering.AIGeminiConfig{
 			Model:        config.Gemini.Model,
 			MaxRetries:   config.Gemini.MaxRetries,
 			MaxLogLength: config.Gemini.MaxLogLength,
-		},
+		}
+	} else if aiConfig.Provider == ai.ProviderGemini {
+		return disabled, fmt.Errorf("gemini configuration is required when ai filter is enabled")
 	}
 
-	matcher, err := newAIMatcher(ctx, config, logger)
+	matcher, err := newAIMatcher(ctx, config, language, logger)
 	if err != nil {
 		return disabled, fmt.Errorf("building ai matcher: %w", err)
 	}
@@ -433,7 +758,7 @@ func prepareAIFilter(ctx context.Context, client *headhunter.Client, config *AIC
 	return filtering.NewAIFit(aiConfig, &filtering.AIFitFilterDeps{
 		Logger:      logger,
 		HH:          client,
-		Resume:      resume,
+		Resumes:     resumes,
 		Matcher:     matcher,
 		ExcludeFile: excludeFile,
 	}), nil
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const defaultExploreTop = 10
+
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Work with the configured vacancy search",
+}
+
+var searchExploreCmd = &cobra.Command{
+	Use:   "explore",
+	Short: "Show the distribution of search results and narrow the search in the config file",
+	Run: func(cmd *cobra.Command, _ []string) {
+		explore(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.AddCommand(searchExploreCmd)
+
+	searchExploreCmd.Flags().String("account", "", "account to search with. Default is the first configured one")
+	searchExploreCmd.Flags().Int("top", defaultExploreTop, "number of values shown per cluster")
+}
+
+// refinement is a cluster value the search can be narrowed to.
+type refinement struct {
+	label string
+	item  *headhunter.ClusterItem
+}
+
+func explore(cmd *cobra.Command) {
+	logger, config := setup()
+	account := singleAccount(cmd, logger, config)
+	top, _ := cmd.Flags().GetInt("top")
+
+	hh, err := openClient(context.Background(), logger.With(zap.String("account", account.Name)), config, account)
+	if err != nil {
+		logger.Fatal("opening account", zap.String("account", account.Name), zap.Error(err))
+	}
+
+	for {
+		if config.Search == nil {
+			logger.Fatal("search section is required")
+		}
+
+		if err := resolveSearch(hh, config, logger); err != nil {
+			logger.Fatal("resolving search parameters", zap.Error(err))
+		}
+
+		result, err := hh.SearchClusters(config.Search)
+		if err != nil {
+			logger.Fatal("searching clusters", zap.Error(err))
+		}
+
+		refinements := printClusters(result, top)
+		if len(refinements) == 0 {
+			return
+		}
+
+		labels := make([]string, 0, len(refinements)+1)
+		for _, r := range refinements {
+			labels = append(labels, r.label)
+		}
+
+		selectPrompt := promptui.Select{
+			Label: "Narrow the search and write it to the config",
+			Items: append(labels, PromptBack),
+			Size:  15,
+		}
+
+		idx, _, err := selectPrompt.Run()
+		if errors.Is(err, promptui.ErrInterrupt) || idx == len(refinements) {
+			return
+		}
+		if err != nil {
+			logger.Fatal("prompt failed", zap.Error(err))
+		}
+
+		if err := applyRefinement(config.Search, refinements[idx].item, logger); err != nil {
+			logger.Fatal("refining the search", zap.Error(err))
+		}
+
+		// Read the config again to get the written values, names included.
+		if err := viper.ReadInConfig(); err != nil {
+			logger.Fatal("reading the config", zap.Error(err))
+		}
+		if config, err = getConfig(); err != nil {
+			logger.Fatal("getting a config", zap.Error(err))
+		}
+	}
+}
+
+// printClusters prints the top values of every cluster and returns them in the printed order.
+func printClusters(result *headhunter.ClusterResult, top int) []refinement {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Found %d vacancies\n", result.Found)
+
+	var refinements []refinement
+	for _, cluster := range result.Clusters {
+		items := append([]*headhunter.ClusterItem(nil), cluster.Items...)
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Count > items[j].Count })
+		if top > 0 && len(items) > top {
+			items = items[:top]
+		}
+
+		fmt.Fprintf(w, "\n%s:\n", cluster.Name)
+		for _, item := range items {
+			fmt.Fprintf(w, "\t%d\t%s\n", item.Count, item.Name)
+			refinements = append(refinements, refinement{
+				label: fmt.Sprintf("%s: %s (%d)", cluster.Name, item.Name, item.Count),
+				item:  item,
+			})
+		}
+	}
+	w.Flush()
+
+	return refinements
+}
+
+// applyRefinement writes the query parameters of the cluster value to the search section of the config file.
+func applyRefinement(search *headhunter.SearchParams, item *headhunter.ClusterItem, logger *zap.Logger) error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return errors.New("config file is unknown")
+	}
+
+	query, err := item.Refinement(search)
+	if err != nil {
+		return err
+	}
+
+	for param, values := range query {
+		key, list, ok := headhunter.SearchParamField(param)
+		if !ok {
+			logger.Warn("skipping unsupported search parameter", zap.String("parameter", param), zap.Strings("values", values))
+			continue
+		}
+
+		if err := setConfigValue(path, "search", key, values, list); err != nil {
+			return fmt.Errorf("writing %s to %s: %w", key, path, err)
+		}
+
+		logger.Info("search refined", zap.String("key", "search."+key), zap.String("value", strings.Join(values, ", ")), zap.String("config", path))
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/dashboard"
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/secrets"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+type DashboardConfig struct {
+	// TokenFile keeps the token required by the dashboard API. Without it a random token is
+	// generated and the dashboard listens on loopback addresses only.
+	TokenFile string `mapstructure:"token-file"`
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start a local web dashboard with vacancies, the exclude list and application history",
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		viper.BindPFlag("exclude-file", cmd.Flags().Lookup("exclude-file"))
+	},
+	Run: func(cmd *cobra.Command, _ []string) {
+		serve(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("listen", "127.0.0.1:8080", "address for the dashboard to listen on")
+	serveCmd.Flags().StringP("exclude-file", "e", "", "special file with vacancies to exclude. Default is unset.")
+	serveCmd.Flags().String("account", "", "account to serve. Default is the first configured one")
+}
+
+func serve(cmd *cobra.Command) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := newSession(ctx, cmd)
+	logger := s.logger
+
+	listen := cmd.Flag("listen").Value.String()
+	token, err := s.config.Dashboard.token()
+	if err != nil {
+		logger.Fatal("loading the dashboard token", zap.Error(err))
+	}
+	if token == "" && !dashboard.IsLoopback(listen) {
+		logger.Fatal("the dashboard sends applications on behalf of the account and must be protected outside of the loopback",
+			zap.String("listen", listen),
+			zap.String("hint", "set dashboard.token-file or HH_DASHBOARD_TOKEN_FILE, or listen on 127.0.0.1"),
+		)
+	}
+
+	server := dashboard.New(dashboardDeps(cmd, s, token))
+
+	if err := server.Refresh(ctx); err != nil {
+		logger.Warn("initial refresh failed", zap.Error(err))
+	}
+
+	httpServer := &http.Server{
+		Addr:              listen,
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("shutting down the dashboard", zap.Error(err))
+		}
+	}()
+
+	if token == "" {
+		logger.Info("starting the dashboard", zap.String("url", "http://"+listen))
+	} else {
+		logger.Info("starting the dashboard", zap.String("url", "http://"+listen), zap.String("hint", "open it with ?token=<dashboard token> once"))
+	}
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal("serving the dashboard", zap.Error(err))
+	}
+}
+
+// dashboardDeps connects the dashboard to the session of the account.
+func dashboardDeps(cmd *cobra.Command, s *session, token string) *dashboard.Deps {
+	logger, excludeFile := s.logger, s.excludeFile
+
+	return &dashboard.Deps{
+		Logger: logger.With(zap.String("frontend", "dashboard")),
+		// The incremental search state is left to runs: a refresh replaces the list and must
+		// keep the vacancies still waiting for review.
+		Collect: func(context.Context) (*headhunter.Vacancies, error) {
+			return s.collect(cmd)
+		},
+		// Applications go the way of apply, queued with the queue enabled. Vacancies handled here
+		// are dropped from the carried ones like the runs do.
+		Apply: func(vacancy *headhunter.Vacancy, message string) error {
+			if err := s.applyEdited(vacancy, message); err != nil {
+				return err
+			}
+
+			s.uncarry(vacancy)
+			return nil
+		},
+		Exclude: func(vacancy *headhunter.Vacancy, reason string) error {
+			if excludeFile == "" {
+				return errors.New("exclude file is not configured")
+			}
+
+			if _, err := appendToExcludeFile(excludeFile, &headhunter.Vacancies{Items: []*headhunter.Vacancy{vacancy}}, reason); err != nil {
+				return err
+			}
+			s.uncarry(vacancy)
+
+			logger.Info("appended to exlude file", zap.String("filename", excludeFile), zap.String("vacancy_id", vacancy.ID))
+			return nil
+		},
+		History:     s.hh.GetNegotiations,
+		ExcludeFile: excludeFile,
+		Token:       token,
+	}
+}
+
+// token returns the configured dashboard token or an empty one to generate.
+func (c *DashboardConfig) token() (string, error) {
+	if c == nil || c.TokenFile == "" {
+		return "", nil
+	}
+
+	return secrets.Load(secrets.Source{
+		Name: "dashboard token",
+		File: c.TokenFile,
+	})
+}
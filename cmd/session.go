@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spigell/hh-responder/internal/filtering"
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/logger"
+	"github.com/spigell/hh-responder/internal/report"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// session holds the config and clients of a single hh.ru account.
+type session struct {
+	ctx     context.Context
+	logger  *zap.Logger
+	config  *Config
+	account *AccountConfig
+	hh      *headhunter.Client
+	// resume is the primary resume. It is used when the AI did not pick one of resumes.
+	resume  *headhunter.Resume
+	resumes []*headhunter.Resume
+	// excludeFile is the exclude list of the account. It may be empty.
+	excludeFile string
+	// multiAccount is set when the run goes over several accounts and their files must not clash.
+	multiAccount bool
+	// applied counts applications sent during the session for the rate-limit budget.
+	applied int
+	// result is optional. When set it collects the outcome of the run.
+	result *report.Result
+	// dropped are vacancies removed by filters during the last collect.
+	dropped []report.Dropped
+	// search is the incremental search waiting for the run to succeed.
+	search *pendingSearch
+	// quota is the daily budget of applications. It is nil when the quota is disabled.
+	quota *dailyQuota
+}
+
+// setup prepares the logger and the config shared by all accounts.
+// Like the rest of the cli it exits on any error.
+func setup() (*zap.Logger, *Config) {
+	// Keep stdout clean when the run result is written there.
+	output := "stdout"
+	if viper.GetString("result-file") == "-" {
+		output = "stderr"
+	}
+
+	logger, err := logger.NewWithOutput(viper.GetBool("json"), viper.GetBool("debug"), output)
+	if err != nil {
+		log.Fatalf("creating a logger: %s", err)
+	}
+
+	config, err := getConfig()
+	if err != nil {
+		logger.Fatal("getting a config", zap.Error(err))
+	}
+
+	logger.Info("starting the hh-responder", zap.String("version", version))
+
+	// do not bother error since there is a valid parseable config
+	pretty, _ := json.MarshalIndent(config, "", "  ")
+	logger.Debug(fmt.Sprintf("starting with config: \n %s", pretty))
+
+	if config == nil {
+		logger.Fatal("config is required")
+	}
+
+	return logger, config
+}
+
+// requireApply exits when the apply section needed to evaluate and apply to vacancies is missing.
+func requireApply(logger *zap.Logger, config *Config) {
+	if config.Apply == nil {
+		logger.Fatal("apply section is required to evaluate and apply to vacancies")
+	}
+}
+
+// singleAccount returns the account chosen by the account flag or the first one.
+// Like the rest of the cli it exits on any error.
+func singleAccount(cmd *cobra.Command, logger *zap.Logger, config *Config) *AccountConfig {
+	accounts, err := selectAccounts(config, cmd.Flag("account").Value.String())
+	if err != nil {
+		logger.Fatal("selecting account", zap.Error(err))
+	}
+
+	if len(accounts) > 1 {
+		logger.Info("several accounts configured, using the first one",
+			zap.String("account", accounts[0].Name),
+			zap.String("hint", "choose another one with --account"),
+		)
+	}
+
+	return accounts[0]
+}
+
+// newSession prepares a session for a single account chosen by the account flag or the first one.
+// Like the rest of the cli it exits on any error.
+func newSession(ctx context.Context, cmd *cobra.Command) *session {
+	logger, config := setup()
+	requireApply(logger, config)
+	account := singleAccount(cmd, logger, config)
+
+	s, err := openSession(ctx, logger, config, account)
+	if err != nil {
+		logger.Fatal("opening account", zap.String("account", account.Name), zap.Error(err))
+	}
+
+	return s
+}
+
+// newClient prepares the headhunter client for the configured site and network. The token may be
+// empty for public endpoints like dictionaries.
+func newClient(ctx context.Context, logger *zap.Logger, config *Config, token string) (*headhunter.Client, error) {
+	hh := headhunter.New(ctx, token, logger)
+	config.API.configure(hh)
+	if err := config.HTTP.configure(hh.HTTPClient); err != nil {
+		return nil, err
+	}
+
+	if _, ok := headhunter.SiteLanguages[config.API.host()]; !ok {
+		logger.Warn("unknown HeadHunter site, make sure the API serves it",
+			zap.String("host", config.API.host()),
+			zap.String("language", config.API.language()),
+		)
+	}
+
+	if config.UserAgent != "" {
+		hh.UserAgent = config.UserAgent
+	}
+
+	return hh, nil
+}
+
+// openClient prepares the headhunter client of the account.
+func openClient(ctx context.Context, logger *zap.Logger, config *Config, account *AccountConfig) (*headhunter.Client, error) {
+	token, refresher, err := resolveToken(ctx, config, account, logger)
+	if err != nil {
+		return nil, fmt.Errorf("loading headhunter token (set HH_TOKEN_FILE environment variable, the 'token-file' key "+
+			"in the configuration file or run auth login with the 'oauth' section configured): %w", err)
+	}
+
+	hh, err := newClient(ctx, logger, config, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if refresher != nil {
+		hh.SetTokenRefresher(refresher.Refresh)
+	}
+
+	if account.RateLimit != nil {
+		hh.SetRateLimit(account.RateLimit.RequestsPerSecond)
+	}
+
+	return hh, nil
+}
+
+// openSession prepares the headhunter client and the resumes of the account.
+func openSession(ctx context.Context, logger *zap.Logger, config *Config, account *AccountConfig) (*session, error) {
+	logger = logger.With(zap.String("account", account.Name))
+
+	titles := account.resumeTitles()
+	if len(titles) == 0 {
+		return nil, errors.New("resume title is required under apply.resume, apply.resumes or accounts[].resume to evaluate and apply to vacancies")
+	}
+
+	hh, err := openClient(ctx, logger, config, account)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveSearch(hh, config, logger); err != nil {
+		return nil, fmt.Errorf("resolving search parameters: %w", err)
+	}
+
+	resumes, err := hh.GetMineResumes()
+	if err != nil {
+		return nil, fmt.Errorf("getting mine resumes: %w", err)
+	}
+
+	logger.Info("getting mine resumes", zap.Int("count", resumes.Len()))
+
+	selected := make([]*headhunter.Resume, 0, len(titles))
+	for _, title := range titles {
+		resume := resumes.FindByTitle(title)
+		if resume == nil {
+			return nil, fmt.Errorf("resume with title %q not found, existed resumes titles: %s",
+				title, strings.Join(resumes.Titles(), ", "))
+		}
+		selected = append(selected, resume)
+	}
+
+	if len(selected) > 1 && (config.AI == nil || !config.AI.Enabled) {
+		logger.Warn("several resumes configured without AI, applying with the first one",
+			zap.String("resume", selected[0].Title),
+		)
+	}
+
+	return &session{
+		ctx:         ctx,
+		logger:      logger,
+		config:      config,
+		account:     account,
+		hh:          hh,
+		resume:      selected[0],
+		resumes:     selected,
+		excludeFile: account.ExcludeFile,
+	}, nil
+}
+
+// resumeFor returns the resume to apply to the vacancy with: the one picked by the AI or the primary one.
+func (s *session) resumeFor(vacancy *headhunter.Vacancy) *headhunter.Resume {
+	if vacancy.AI != nil && vacancy.AI.ResumeID != "" {
+		for _, resume := range s.resumes {
+			if resume.ID == vacancy.AI.ResumeID {
+				return resume
+			}
+		}
+	}
+
+	return s.resume
+}
+
+// accountPath makes a per-account file name when several accounts share a run.
+func (s *session) accountPath(path string) string {
+	if !s.multiAccount || path == "" || path == "-" {
+		return path
+	}
+
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + s.account.Name + ext
+}
+
+// collect searches for vacancies and runs them through the filters.
+func (s *session) collect(cmd *cobra.Command) (*headhunter.Vacancies, error) {
+	s.logger.Info("starting the search", zap.String("search", s.config.Search.Text))
+
+	params := s.searchParams(cmd)
+	if s.result != nil {
+		s.result.Search = params
+	}
+
+	vacancies, err := getVacancies(s.hh, params, s.logger)
+	if err != nil {
+		return nil, fmt.Errorf("getting available vacancies: %w", err)
+	}
+
+	vacancies = s.withCarried(vacancies)
+
+	if vacancies.Len() == 0 {
+		s.logger.Info("no vacancies found")
+		return vacancies, nil
+	}
+
+	filters := prepareFilters(cmd, s)
+
+	found := vacancies.Len()
+
+	filtered, decisions, err := filters.RunFilters(s.ctx, vacancies)
+	if err != nil {
+		return nil, fmt.Errorf("filtering failed: %w", err)
+	}
+
+	s.dropped = make([]report.Dropped, 0, len(decisions))
+	for _, decision := range decisions {
+		s.logger.Debug("vacancy dropped",
+			zap.String("vacancy_id", decision.Vacancy.ID),
+			zap.String("filter", decision.Filter),
+			zap.String("reason", decision.Reason),
+		)
+		s.dropped = append(s.dropped, report.Dropped{Vacancy: decision.Vacancy, Filter: decision.Filter, Reason: decision.Reason})
+	}
+
+	if s.result != nil {
+		s.recordFiltering(found, filters, filtered)
+	}
+
+	return filtered, nil
+}
+
+func (s *session) recordFiltering(found int, filters *filtering.Filtering, filtered *headhunter.Vacancies) {
+	s.result.Found = found
+	s.result.SetKept(filtered)
+
+	for _, step := range filters.Steps() {
+		s.result.Steps = append(s.result.Steps, report.ResultStep{
+			Name:    step.Name,
+			Enabled: step.Enabled,
+			Initial: step.Initial,
+			Dropped: step.Dropped,
+			Left:    step.Left,
+		})
+	}
+
+	for _, drop := range s.dropped {
+		dropped := report.NewResultVacancy(drop.Vacancy)
+		dropped.Filter = drop.Filter
+		dropped.Reason = drop.Reason
+		s.result.Dropped = append(s.result.Dropped, dropped)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/oauth"
+)
+
+// host returns the configured HeadHunter site, hh.ru by default.
+func (c *APIConfig) host() string {
+	if c == nil || strings.TrimSpace(c.Host) == "" {
+		return headhunter.DefaultHost
+	}
+	return strings.ToLower(strings.TrimSpace(c.Host))
+}
+
+// locale returns the configured locale of names in responses. Empty means the API default.
+func (c *APIConfig) locale() string {
+	if c == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(c.Locale))
+}
+
+// language returns the language of cover letters when the vacancy language is unclear.
+func (c *APIConfig) language() string {
+	return headhunter.SiteLanguage(c.host())
+}
+
+// configure points the client to the configured API and site.
+func (c *APIConfig) configure(hh *headhunter.Client) {
+	if c == nil {
+		return
+	}
+
+	if url := strings.TrimRight(strings.TrimSpace(c.URL), "/"); url != "" {
+		hh.APIURL = url
+	}
+	if strings.TrimSpace(c.Host) != "" {
+		hh.Host = c.host()
+	}
+	hh.Locale = c.locale()
+}
+
+// configureOAuth points the OAuth endpoints to the configured API and site.
+func (c *APIConfig) configureOAuth(config *oauth.Config) {
+	if c == nil {
+		return
+	}
+
+	if strings.TrimSpace(c.Host) != "" {
+		config.AuthURL = "https://" + c.host() + "/oauth/authorize"
+	}
+	if url := strings.TrimRight(strings.TrimSpace(c.URL), "/"); url != "" {
+		config.TokenURL = url + "/token"
+	}
+}
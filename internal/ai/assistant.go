@@ -6,6 +6,12 @@ import (
 	"github.com/spigell/hh-responder/internal/headhunter"
 )
 
+const (
+	ProviderGemini = "gemini"
+	// ProviderFake answers with scripted or replayed assessments without calling any API.
+	ProviderFake = "fake"
+)
+
 type FitAssessment struct {
 	Fit     bool
 	Score   float64
@@ -17,3 +23,16 @@ type FitAssessment struct {
 type Matcher interface {
 	Evaluate(ctx context.Context, resumePayload map[string]any, vacancy *headhunter.Vacancy) (*FitAssessment, error)
 }
+
+// ResumeAdvice is a gap analysis of the resume against the market demand.
+type ResumeAdvice struct {
+	Summary       string
+	MissingSkills []string
+	Wording       []string
+	Titles        []string
+	Raw           string
+}
+
+type Advisor interface {
+	Advise(ctx context.Context, resumePayload map[string]any, demand *MarketDemand) (*ResumeAdvice, error)
+}
@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+const maxDemandRequirements = 50
+
+// MarketDemand aggregates what the vacancies ask for.
+type MarketDemand struct {
+	Vacancies    int           `json:"vacancies"`
+	Skills       []DemandCount `json:"skills"`
+	Titles       []DemandCount `json:"titles"`
+	Requirements []string      `json:"requirements"`
+}
+
+// DemandCount is the number of vacancies mentioning the name.
+type DemandCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NewMarketDemand counts key skills and titles of the vacancies and collects their requirements.
+// Names differing only in case are counted together.
+func NewMarketDemand(vacancies []*headhunter.Vacancy) *MarketDemand {
+	skills := newCounter()
+	titles := newCounter()
+	seen := make(map[string]bool)
+
+	demand := &MarketDemand{Vacancies: len(vacancies)}
+	for _, vacancy := range vacancies {
+		for _, skill := range vacancy.KeySkills {
+			skills.add(skill.Name)
+		}
+		titles.add(vacancy.Name)
+
+		requirement := strings.TrimSpace(headhunter.StripHTML(vacancy.Snipet.Requirement))
+		key := strings.ToLower(requirement)
+		if requirement == "" || seen[key] || len(demand.Requirements) >= maxDemandRequirements {
+			continue
+		}
+		seen[key] = true
+		demand.Requirements = append(demand.Requirements, requirement)
+	}
+
+	demand.Skills = skills.sorted()
+	demand.Titles = titles.sorted()
+
+	return demand
+}
+
+type counter struct {
+	names  map[string]string
+	counts map[string]int
+}
+
+func newCounter() *counter {
+	return &counter{names: make(map[string]string), counts: make(map[string]int)}
+}
+
+func (c *counter) add(name string) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return
+	}
+
+	key := strings.ToLower(name)
+	if _, ok := c.names[key]; !ok {
+		c.names[key] = name
+	}
+	c.counts[key]++
+}
+
+// sorted returns the counts from the most to the least frequent. Ties are ordered by name.
+func (c *counter) sorted() []DemandCount {
+	result := make([]DemandCount, 0, len(c.counts))
+	for key, count := range c.counts {
+		result = append(result, DemandCount{Name: c.names[key], Count: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+func TestNewMarketDemand(t *testing.T) {
+	vacancy := func(name, requirement string, skills ...string) *headhunter.Vacancy {
+		v := &headhunter.Vacancy{Name: name}
+		v.Snipet.Requirement = requirement
+		for _, skill := range skills {
+			v.KeySkills = append(v.KeySkills, struct {
+				Name string `json:"name,omitempty"`
+			}{Name: skill})
+		}
+		return v
+	}
+
+	demand := NewMarketDemand([]*headhunter.Vacancy{
+		vacancy("SRE", "<highlighttext>Kubernetes</highlighttext> experience", "Kubernetes", "Go"),
+		vacancy("DevOps", "Kubernetes experience", "kubernetes", "Terraform"),
+		vacancy("SRE", "", "Go", " "),
+	})
+
+	if demand.Vacancies != 3 {
+		t.Fatalf("expected 3 vacancies, got %d", demand.Vacancies)
+	}
+
+	expectedSkills := []DemandCount{{Name: "Go", Count: 2}, {Name: "Kubernetes", Count: 2}, {Name: "Terraform", Count: 1}}
+	if !reflect.DeepEqual(demand.Skills, expectedSkills) {
+		t.Fatalf("unexpected skills: %+v", demand.Skills)
+	}
+
+	expectedTitles := []DemandCount{{Name: "SRE", Count: 2}, {Name: "DevOps", Count: 1}}
+	if !reflect.DeepEqual(demand.Titles, expectedTitles) {
+		t.Fatalf("unexpected titles: %+v", demand.Titles)
+	}
+
+	if !reflect.DeepEqual(demand.Requirements, []string{"Kubernetes experience"}) {
+		t.Fatalf("unexpected requirements: %q", demand.Requirements)
+	}
+}
@@ -0,0 +1,168 @@
+// Package fake is an AI provider that answers with scripted or replayed assessments.
+// It needs no API key, so the ai_fit path can run in CI and demos.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/spigell/hh-responder/internal/ai"
+	"github.com/spigell/hh-responder/internal/ai/gemini"
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/report"
+)
+
+// Script describes the answers of the provider. A vacancy is looked up by its ID in Vacancies,
+// then in recorded Responses, then matched against Keywords in order. Default answers the rest.
+// Without Default an unmatched vacancy is an evaluation error.
+type Script struct {
+	Default   *Assessment            `yaml:"default"`
+	Vacancies map[string]*Assessment `yaml:"vacancies"`
+	Keywords  []*KeywordRule         `yaml:"keywords"`
+	// Responses are raw model responses keyed by vacancy ID. They are parsed like Gemini ones.
+	Responses map[string]string `yaml:"responses"`
+}
+
+// Assessment is a scripted answer.
+type Assessment struct {
+	Fit     bool    `yaml:"fit" json:"fit"`
+	Score   float64 `yaml:"score" json:"score"`
+	Reason  string  `yaml:"reason" json:"reason"`
+	Message string  `yaml:"message" json:"message"`
+}
+
+// KeywordRule answers vacancies mentioning the keyword in the name, key skills or description.
+type KeywordRule struct {
+	Keyword    string `yaml:"keyword"`
+	Assessment `yaml:",inline"`
+}
+
+// LoadScript reads the script from a YAML or JSON file.
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, so both formats are decoded the same way.
+	var script Script
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("decoding ai script %s: %w", path, err)
+	}
+
+	return &script, nil
+}
+
+// Replay adds the raw responses recorded in the run result file. Responses already in the script win.
+func (s *Script) Replay(path string) (int, error) {
+	run, err := report.ReadRunFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.Responses == nil {
+		s.Responses = make(map[string]string)
+	}
+
+	added := 0
+	for _, result := range run.Accounts {
+		for _, vacancies := range [][]report.ResultVacancy{result.Kept, result.Dropped} {
+			for _, vacancy := range vacancies {
+				if vacancy.AI == nil || vacancy.AI.Raw == "" {
+					continue
+				}
+				if _, ok := s.Responses[vacancy.ID]; ok {
+					continue
+				}
+				s.Responses[vacancy.ID] = vacancy.AI.Raw
+				added++
+			}
+		}
+	}
+
+	return added, nil
+}
+
+// Matcher implements ai.Matcher with the script.
+type Matcher struct {
+	script   *Script
+	minScore float64
+	logger   *zap.Logger
+}
+
+func NewMatcher(script *Script, minScore float64, logger *zap.Logger) *Matcher {
+	return &Matcher{
+		script:   script,
+		minScore: minScore,
+		logger:   logger,
+	}
+}
+
+func (m *Matcher) Evaluate(_ context.Context, _ map[string]any, vacancy *headhunter.Vacancy) (*ai.FitAssessment, error) {
+	assessment, source, err := m.answer(vacancy)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.Debug("fake assessment",
+		zap.String("vacancy_id", vacancy.ID),
+		zap.String("source", source),
+		zap.Bool("fit", assessment.Fit),
+		zap.Float64("score", assessment.Score),
+	)
+
+	if m.minScore > 0 && !math.IsNaN(assessment.Score) && assessment.Score < m.minScore {
+		assessment.Fit = false
+	}
+
+	return assessment, nil
+}
+
+// answer returns the assessment of the vacancy and the part of the script it came from.
+func (m *Matcher) answer(vacancy *headhunter.Vacancy) (*ai.FitAssessment, string, error) {
+	if scripted, ok := m.script.Vacancies[vacancy.ID]; ok && scripted != nil {
+		return scripted.assessment(), "vacancy", nil
+	}
+
+	if raw, ok := m.script.Responses[vacancy.ID]; ok {
+		assessment, err := gemini.ParseResponse(raw)
+		if err != nil {
+			return nil, "", fmt.Errorf("replaying response of vacancy %s: %w", vacancy.ID, err)
+		}
+		assessment.Raw = raw
+		return assessment, "replay", nil
+	}
+
+	text := strings.ToLower(strings.Join(append([]string{vacancy.Name, vacancy.PlainDescription()}, vacancy.KeySkillNames()...), "\n"))
+	for _, rule := range m.script.Keywords {
+		if keyword := strings.ToLower(strings.TrimSpace(rule.Keyword)); keyword != "" && strings.Contains(text, keyword) {
+			return rule.assessment(), "keyword " + rule.Keyword, nil
+		}
+	}
+
+	if m.script.Default != nil {
+		return m.script.Default.assessment(), "default", nil
+	}
+
+	return nil, "", fmt.Errorf("no scripted assessment for vacancy %s", vacancy.ID)
+}
+
+// assessment converts the answer. Raw keeps it as JSON, so a run result can be replayed later.
+func (a *Assessment) assessment() *ai.FitAssessment {
+	raw, _ := json.Marshal(a)
+
+	return &ai.FitAssessment{
+		Fit:     a.Fit,
+		Score:   a.Score,
+		Reason:  a.Reason,
+		Message: a.Message,
+		Raw:     string(raw),
+	}
+}
@@ -0,0 +1,105 @@
+package fake
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/report"
+)
+
+const script = `
+default:
+  fit: false
+  score: 0.1
+  reason: no rule matched
+vacancies:
+  "1":
+    fit: true
+    score: 0.95
+    message: Scripted letter
+keywords:
+  - keyword: Kubernetes
+    fit: true
+    score: 0.8
+    reason: mentions Kubernetes
+  - keyword: go
+    fit: true
+    score: 0.5
+responses:
+  "2": "` + "```json\\n{\\\"fit\\\": true, \\\"score\\\": \\\"0.7\\\", \\\"reason\\\": \\\"Recorded\\\"}\\n```" + `"
+`
+
+func TestMatcher(t *testing.T) {
+	dir := t.TempDir()
+	scriptFile := filepath.Join(dir, "script.yaml")
+	if err := os.WriteFile(scriptFile, []byte(script), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadScript(scriptFile)
+	if err != nil {
+		t.Fatalf("loading script: %v", err)
+	}
+
+	// The response of vacancy 2 is in the script already, so only vacancy 3 is replayed.
+	run := report.NewRun("test")
+	run.AddAccount("main", nil).SetKept(&headhunter.Vacancies{Items: []*headhunter.Vacancy{
+		{ID: "2", AI: &headhunter.AIAssessment{Raw: `{"fit": false}`}},
+		{ID: "3", AI: &headhunter.AIAssessment{Raw: `{"fit": true, "score": 0.65, "message": "Replayed letter"}`}},
+		{ID: "4"},
+	}})
+	runFile := filepath.Join(dir, "result.json")
+	if err := run.WriteFile(runFile); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := loaded.Replay(runFile)
+	if err != nil || replayed != 1 {
+		t.Fatalf("expected 1 replayed response, got %d: %v", replayed, err)
+	}
+
+	matcher := NewMatcher(loaded, 0.6, zap.NewNop())
+
+	tests := []struct {
+		name    string
+		vacancy *headhunter.Vacancy
+		fit     bool
+		score   float64
+		text    string
+	}{
+		{name: "by id", vacancy: &headhunter.Vacancy{ID: "1"}, fit: true, score: 0.95, text: "Scripted letter"},
+		{name: "recorded response", vacancy: &headhunter.Vacancy{ID: "2"}, fit: true, score: 0.7, text: "Recorded"},
+		{name: "replayed run result", vacancy: &headhunter.Vacancy{ID: "3"}, fit: true, score: 0.65, text: "Replayed letter"},
+		{name: "first keyword wins", vacancy: &headhunter.Vacancy{ID: "5", Name: "Go developer", Description: "<p>We run kubernetes</p>"}, fit: true, score: 0.8, text: "mentions Kubernetes"},
+		{name: "score below threshold", vacancy: &headhunter.Vacancy{ID: "6", Name: "Go developer"}, fit: false, score: 0.5},
+		{name: "default", vacancy: &headhunter.Vacancy{ID: "7", Name: "Accountant"}, fit: false, score: 0.1, text: "no rule matched"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment, err := matcher.Evaluate(context.Background(), nil, tt.vacancy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if assessment.Fit != tt.fit || assessment.Score != tt.score {
+				t.Fatalf("expected fit=%v score=%v, got %+v", tt.fit, tt.score, assessment)
+			}
+
+			if !strings.Contains(assessment.Message+assessment.Reason, tt.text) || assessment.Raw == "" {
+				t.Fatalf("unexpected assessment %+v", assessment)
+			}
+		})
+	}
+
+	loaded.Default = nil
+	if _, err := matcher.Evaluate(context.Background(), nil, &headhunter.Vacancy{ID: "7"}); err == nil {
+		t.Fatal("expected an error without default assessment")
+	}
+}
@@ -0,0 +1,124 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	_ "embed"
+
+	"github.com/spigell/hh-responder/internal/ai"
+	"github.com/spigell/hh-responder/internal/logger"
+	"go.uber.org/zap"
+)
+
+//go:embed advice_prompt.md
+var advicePromptTemplate string
+
+// Advisor suggests resume improvements based on the market demand.
+type Advisor struct {
+	generator contentGenerator
+	logger    *zap.Logger
+	maxLogLen int
+	language  string
+}
+
+func NewAdvisor(generator contentGenerator, maxLogLength int, logger *zap.Logger) *Advisor {
+	if maxLogLength <= 0 {
+		maxLogLength = defaultMaxLogLength
+	}
+
+	return &Advisor{
+		generator: generator,
+		logger:    logger,
+		maxLogLen: maxLogLength,
+		language:  defaultLanguage,
+	}
+}
+
+// SetLanguage sets the language of the advice when the resume language is unclear.
+func (a *Advisor) SetLanguage(language string) {
+	a.language = sanitizeSingleLine(language, defaultLanguage)
+}
+
+func (a *Advisor) Advise(ctx context.Context, resumePayload map[string]any, demand *ai.MarketDemand) (*ai.ResumeAdvice, error) {
+	if demand == nil || demand.Vacancies == 0 {
+		return nil, errors.New("market demand is empty")
+	}
+
+	resumeJSON, err := json.MarshalIndent(resumePayload, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("marshal resume payload: %w", err)
+	}
+
+	marketJSON, err := json.MarshalIndent(demand, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal market demand: %w", err)
+	}
+
+	prompt := strings.NewReplacer(
+		"{{RESUME_JSON}}", string(resumeJSON),
+		"{{MARKET_JSON}}", string(marketJSON),
+		"{{default_language}}", a.language,
+	).Replace(advicePromptTemplate)
+
+	a.logger.Debug("gemini generate advice request",
+		zap.Int("vacancies", demand.Vacancies),
+		zap.Int("prompt_length", utf8.RuneCountInString(prompt)),
+		zap.String("prompt_preview", logger.TruncateForLog(prompt, a.maxLogLen)),
+	)
+
+	raw, err := a.generator.GenerateContent(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Debug("gemini generate advice response",
+		zap.Int("response_length", utf8.RuneCountInString(raw)),
+		zap.String("response_preview", logger.TruncateForLog(raw, a.maxLogLen)),
+	)
+
+	advice, err := parseAdvice(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	advice.Raw = raw
+	return advice, nil
+}
+
+func parseAdvice(raw string) (*ai.ResumeAdvice, error) {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(extractJSON(raw)), &data); err != nil {
+		return nil, fmt.Errorf("parse gemini response: %w", err)
+	}
+
+	return &ai.ResumeAdvice{
+		Summary:       coerceString(data["summary"]),
+		MissingSkills: coerceStrings(data["missing_skills"]),
+		Wording:       coerceStrings(data["wording"]),
+		Titles:        coerceStrings(data["titles"]),
+	}, nil
+}
+
+func coerceStrings(v any) []string {
+	switch val := v.(type) {
+	case []any:
+		result := make([]string, 0, len(val))
+		for _, item := range val {
+			if s := coerceString(item); s != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	case string:
+		if s := strings.TrimSpace(val); s != "" {
+			return []string{s}
+		}
+	}
+
+	return nil
+}
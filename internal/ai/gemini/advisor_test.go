@@ -0,0 +1,45 @@
+package gemini
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spigell/hh-responder/internal/ai"
+	"go.uber.org/zap"
+)
+
+func TestAdvisorAdvise(t *testing.T) {
+	stub := &stubGenerator{response: "```json\n" + `{"summary": "Close to market", "missing_skills": ["Terraform", " "], "wording": "Mention on-call", "titles": ["SRE", "DevOps engineer"]}` + "\n```"}
+	advisor := NewAdvisor(stub, 0, zap.NewNop())
+
+	demand := &ai.MarketDemand{Vacancies: 2, Skills: []ai.DemandCount{{Name: "Terraform", Count: 2}}}
+	advice, err := advisor.Advise(context.Background(), map[string]any{"title": "DevOps"}, demand)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if advice.Summary != "Close to market" {
+		t.Fatalf("unexpected summary: %q", advice.Summary)
+	}
+
+	if strings.Join(advice.MissingSkills, ",") != "Terraform" {
+		t.Fatalf("unexpected missing skills: %v", advice.MissingSkills)
+	}
+
+	if strings.Join(advice.Wording, ",") != "Mention on-call" {
+		t.Fatalf("unexpected wording: %v", advice.Wording)
+	}
+
+	if len(advice.Titles) != 2 {
+		t.Fatalf("unexpected titles: %v", advice.Titles)
+	}
+
+	if !strings.Contains(stub.lastPrompt, `"name": "Terraform"`) || !strings.Contains(stub.lastPrompt, `"title": "DevOps"`) {
+		t.Fatalf("expected resume and market in prompt:\n%s", stub.lastPrompt)
+	}
+
+	if _, err := advisor.Advise(context.Background(), nil, &ai.MarketDemand{}); err == nil {
+		t.Fatalf("expected error for empty demand")
+	}
+}
@@ -28,6 +28,7 @@ type Matcher struct {
 	logger    *zap.Logger
 	maxLogLen int
 	overrides promptOverrides
+	language  string
 }
 
 //go:embed prompt.md
@@ -35,6 +36,9 @@ var promptTemplate string
 
 const defaultMaxLogLength = 200
 
+// defaultLanguage is used by prompts when the vacancy or the resume language is unclear.
+const defaultLanguage = "Russian"
+
 const (
 	defaultOverrideValue    = "none"
 	defaultToneValue        = "Friendly"
@@ -72,6 +76,7 @@ func NewMatcher(generator contentGenerator, minScore float64, maxLogLength int,
 		minScore:  minScore,
 		logger:    logger,
 		maxLogLen: maxLogLength,
+		language:  defaultLanguage,
 	}
 
 	matcher.SetPromptOverrides(PromptOverrides{})
@@ -112,7 +117,7 @@ func (m *Matcher) Evaluate(ctx context.Context, resumePayload map[string]any, va
 		zap.String("response_preview", logger.TruncateForLog(raw, m.maxLogLen)),
 	)
 
-	assessment, err := parseResponse(raw)
+	assessment, err := ParseResponse(raw)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +139,11 @@ func (m *Matcher) SetPromptOverrides(overrides PromptOverrides) {
 	m.overrides = sanitizePromptOverrides(overrides)
 }
 
+// SetLanguage sets the language of the message when the vacancy language is unclear.
+func (m *Matcher) SetLanguage(language string) {
+	m.language = sanitizeSingleLine(language, defaultLanguage)
+}
+
 func (m *Matcher) buildPrompt(resumeJSON, vacancyJSON string) string {
 	template := promptTemplate
 	if strings.TrimSpace(template) == "" {
@@ -157,6 +167,7 @@ func (m *Matcher) buildPrompt(resumeJSON, vacancyJSON string) string {
 		"{{tone}}", m.overrides.Tone,
 		"{{region_constraints}}", m.overrides.RegionConstraints,
 		"{{user_instructions_sanitized}}", formatUserInstructions(m.overrides.UserInstructions),
+		"{{default_language}}", m.language,
 	)
 
 	return replacer.Replace(template)
@@ -303,7 +314,8 @@ func sanitizeText(value string, allowNewlines bool) string {
 	return strings.TrimSpace(cleaned)
 }
 
-func parseResponse(raw string) (*ai.FitAssessment, error) {
+// ParseResponse converts the raw model response to the assessment. It tolerates fenced JSON and loosely typed values.
+func ParseResponse(raw string) (*ai.FitAssessment, error) {
 	cleaned := strings.TrimSpace(raw)
 	cleaned = extractJSON(cleaned)
 
@@ -0,0 +1,98 @@
+package dashboard
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// TokenHeader carries the token for scripts calling the API.
+	TokenHeader = "X-Dashboard-Token"
+	tokenCookie = "hh-dashboard-token"
+)
+
+// IsLoopback reports whether the address like 127.0.0.1:8080 or localhost is reachable only from this machine.
+// An empty host means all interfaces.
+func IsLoopback(address string) bool {
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// guard checks every request except the index page for the token and rejects state-changing
+// requests coming from other sites.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A generated token is handed out to anyone opening the page, so the page must not be
+		// reachable through a foreign host name pointing to the loopback (DNS rebinding).
+		if s.shareToken && !IsLoopback(r.Host) {
+			writeError(w, http.StatusForbidden, errors.New("dashboard is available on a loopback address only"))
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && crossSite(r) {
+			writeError(w, http.StatusForbidden, errors.New("cross-site request"))
+			return
+		}
+
+		if r.URL.Path != "/" && !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, errors.New("dashboard token is missing or invalid"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if token := r.Header.Get(TokenHeader); token != "" {
+		return s.validToken(token)
+	}
+
+	cookie, err := r.Cookie(tokenCookie)
+	return err == nil && s.validToken(cookie.Value)
+}
+
+func (s *Server) validToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// setTokenCookie lets the page call the API. Other sites can't send it since it is strict.
+func (s *Server) setTokenCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     tokenCookie,
+		Value:    s.token,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// crossSite reports requests made by pages of other origins.
+func crossSite(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "", "same-origin", "none":
+	default:
+		return true
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	return err != nil || !strings.EqualFold(u.Host, r.Host)
+}
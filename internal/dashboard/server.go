@@ -0,0 +1,318 @@
+package dashboard
+
+import (
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+var errNotExcluded = errors.New("vacancy is not excluded")
+
+// Deps are the callbacks the dashboard uses to reach the pipeline and the hh.ru account.
+type Deps struct {
+	Logger *zap.Logger
+	// Collect searches for vacancies and runs them through the filters.
+	Collect func(ctx context.Context) (*headhunter.Vacancies, error)
+	// Apply sends an application. An empty message means the configured default.
+	Apply   func(vacancy *headhunter.Vacancy, message string) error
+	Exclude func(vacancy *headhunter.Vacancy, reason string) error
+	History func() (*headhunter.Negotations, error)
+	// ExcludeFile is the exclude list managed by the dashboard. It may be empty.
+	ExcludeFile string
+	// Token authenticates API requests. Visitors open the page with ?token= once to get it.
+	// When it is empty a random token is generated and handed out by the page itself,
+	// so the dashboard must listen on a loopback address then.
+	Token string
+}
+
+// Server serves the web UI and the JSON API over the latest pipeline results.
+type Server struct {
+	deps *Deps
+
+	token string
+	// shareToken is set for the generated token the page sets to every visitor.
+	shareToken bool
+
+	mu          sync.Mutex
+	vacancies   *headhunter.Vacancies
+	refreshedAt time.Time
+	// inFlight are vacancies with an action running. Guarded by mu.
+	inFlight map[string]bool
+
+	// accountMu serializes applications and refreshes, they share the budget and the state of the account.
+	accountMu sync.Mutex
+}
+
+type vacanciesResponse struct {
+	RefreshedAt time.Time             `json:"refreshed_at"`
+	Items       []*headhunter.Vacancy `json:"items"`
+}
+
+type historyItem struct {
+	ID          string `json:"id"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+	State       string `json:"state,omitempty"`
+	VacancyID   string `json:"vacancy_id,omitempty"`
+	VacancyName string `json:"vacancy_name,omitempty"`
+	Employer    string `json:"employer,omitempty"`
+}
+
+type actionRequest struct {
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+}
+
+func New(deps *Deps) *Server {
+	server := &Server{
+		deps:      deps,
+		token:     deps.Token,
+		vacancies: &headhunter.Vacancies{},
+		inFlight:  make(map[string]bool),
+	}
+
+	if server.token == "" {
+		server.token = rand.Text()
+		server.shareToken = true
+	}
+
+	return server
+}
+
+// Handler returns the HTTP handler with the UI and API routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /api/vacancies", s.listVacancies)
+	mux.HandleFunc("POST /api/refresh", s.refresh)
+	mux.HandleFunc("POST /api/vacancies/{id}/apply", s.applyVacancy)
+	mux.HandleFunc("POST /api/vacancies/{id}/exclude", s.excludeVacancy)
+	mux.HandleFunc("GET /api/excluded", s.listExcluded)
+	mux.HandleFunc("DELETE /api/excluded/{id}", s.removeExcluded)
+	mux.HandleFunc("GET /api/history", s.history)
+
+	return s.guard(mux)
+}
+
+// Refresh runs the pipeline and replaces the vacancies shown in the dashboard.
+func (s *Server) Refresh(ctx context.Context) error {
+	s.accountMu.Lock()
+	defer s.accountMu.Unlock()
+
+	vacancies, err := s.deps.Collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.vacancies = vacancies
+	s.refreshedAt = time.Now().UTC()
+
+	s.deps.Logger.Info("dashboard vacancies refreshed", zap.Int("count", vacancies.Len()))
+
+	return nil
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	if token := r.URL.Query().Get("token"); token != "" {
+		if !s.validToken(token) {
+			writeError(w, http.StatusUnauthorized, errors.New("invalid dashboard token"))
+			return
+		}
+
+		// Keep the token out of the address bar and the history.
+		s.setTokenCookie(w)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	if s.shareToken {
+		s.setTokenCookie(w)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(indexHTML)
+}
+
+func (s *Server) listVacancies(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	response := vacanciesResponse{
+		RefreshedAt: s.refreshedAt,
+		Items:       append([]*headhunter.Vacancy{}, s.vacancies.Items...),
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	if err := s.Refresh(r.Context()); err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("refresh: %w", err))
+		return
+	}
+
+	s.listVacancies(w, r)
+}
+
+func (s *Server) applyVacancy(w http.ResponseWriter, r *http.Request) {
+	s.act(w, r, func(vacancy *headhunter.Vacancy, req actionRequest) error {
+		s.accountMu.Lock()
+		defer s.accountMu.Unlock()
+
+		return s.deps.Apply(vacancy, strings.TrimSpace(req.Message))
+	})
+}
+
+func (s *Server) excludeVacancy(w http.ResponseWriter, r *http.Request) {
+	s.act(w, r, func(vacancy *headhunter.Vacancy, req actionRequest) error {
+		reason := strings.TrimSpace(req.Reason)
+		if reason == "" {
+			reason = headhunter.ExcludeReasonManualApply
+		}
+
+		return s.deps.Exclude(vacancy, reason)
+	})
+}
+
+// act runs the action for the vacancy and drops it from the list on success. Only one action
+// runs for a vacancy at a time.
+func (s *Server) act(w http.ResponseWriter, r *http.Request, action func(*headhunter.Vacancy, actionRequest) error) {
+	id := r.PathValue("id")
+
+	var req actionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("decode request: %w", err))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	vacancy := s.vacancies.FindByID(id)
+	busy := s.inFlight[id]
+	if vacancy != nil && !busy {
+		s.inFlight[id] = true
+	}
+	s.mu.Unlock()
+
+	if vacancy == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("vacancy %s not found", id))
+		return
+	}
+	if busy {
+		writeError(w, http.StatusConflict, fmt.Errorf("vacancy %s is being processed", id))
+		return
+	}
+
+	err := action(vacancy, req)
+
+	s.mu.Lock()
+	delete(s.inFlight, id)
+	if err == nil {
+		s.vacancies.Exclude(headhunter.VacancyIDField, []string{id})
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id": id, "status": "ok"})
+}
+
+func (s *Server) listExcluded(w http.ResponseWriter, _ *http.Request) {
+	if s.deps.ExcludeFile == "" {
+		writeJSON(w, http.StatusOK, &headhunter.ExcludedVacancies{Items: []*headhunter.ExcludedVacancy{}})
+		return
+	}
+
+	excluded, err := headhunter.GetExludedVacanciesFromFile(s.deps.ExcludeFile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, excluded)
+}
+
+func (s *Server) removeExcluded(w http.ResponseWriter, r *http.Request) {
+	if s.deps.ExcludeFile == "" {
+		writeError(w, http.StatusConflict, errors.New("exclude file is not configured"))
+		return
+	}
+
+	id := r.PathValue("id")
+
+	_, err := headhunter.UpdateExcludedFile(s.deps.ExcludeFile, func(excluded *headhunter.ExcludedVacancies) error {
+		if !excluded.Remove(id) {
+			return errNotExcluded
+		}
+		return nil
+	})
+	if errors.Is(err, errNotExcluded) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("vacancy %s is not excluded", id))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.deps.Logger.Info("vacancy removed from exclude file", zap.String("vacancy_id", id))
+
+	writeJSON(w, http.StatusOK, map[string]string{"id": id, "status": "ok"})
+}
+
+func (s *Server) history(w http.ResponseWriter, _ *http.Request) {
+	negotiations, err := s.deps.History()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("get negotiations: %w", err))
+		return
+	}
+
+	items := make([]historyItem, 0, len(*negotiations))
+	for _, n := range *negotiations {
+		item := historyItem{
+			ID:        n.ID,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+			State:     n.State.Name,
+		}
+		if n.Vacancy != nil {
+			item.VacancyID = n.Vacancy.ID
+			item.VacancyName = n.Vacancy.Name
+			item.Employer = n.Vacancy.Employer.Name
+		}
+		items = append(items, item)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
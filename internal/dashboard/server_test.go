@@ -0,0 +1,364 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+const testToken = "test-token"
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server, map[string]string) {
+	t.Helper()
+
+	excludeFile := filepath.Join(t.TempDir(), "excluded.json")
+	if err := os.WriteFile(excludeFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	applied := make(map[string]string)
+
+	deps := &Deps{
+		Logger: zap.NewNop(),
+		Collect: func(context.Context) (*headhunter.Vacancies, error) {
+			return &headhunter.Vacancies{Items: []*headhunter.Vacancy{
+				{ID: "1", Name: "Go Developer"},
+				{ID: "2", Name: "SRE"},
+				{ID: "3", Name: "Broken"},
+			}}, nil
+		},
+		Apply: func(vacancy *headhunter.Vacancy, message string) error {
+			if vacancy.ID == "3" {
+				return errors.New("bad status: 403 Forbidden")
+			}
+			applied[vacancy.ID] = message
+			return nil
+		},
+		Exclude: func(vacancy *headhunter.Vacancy, reason string) error {
+			_, err := headhunter.UpdateExcludedFile(excludeFile, func(excluded *headhunter.ExcludedVacancies) error {
+				excluded.Append((&headhunter.Vacancies{Items: []*headhunter.Vacancy{vacancy}}).ToExcluded(headhunter.ExcludeActorHuman, reason))
+				return nil
+			})
+			return err
+		},
+		History: func() (*headhunter.Negotations, error) {
+			return &headhunter.Negotations{{ID: "n1", Vacancy: &headhunter.Vacancy{ID: "7", Name: "Old"}}}, nil
+		},
+		ExcludeFile: excludeFile,
+		Token:       testToken,
+	}
+
+	server := New(deps)
+	httpServer := httptest.NewServer(server.Handler())
+	t.Cleanup(httpServer.Close)
+
+	return server, httpServer, applied
+}
+
+func do(t *testing.T, method, url, body string, target any) int {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(TokenHeader, testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if target != nil {
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			t.Fatalf("decode %s %s: %v", method, url, err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+func TestDashboardVacanciesFlow(t *testing.T) {
+	_, server, applied := newTestServer(t)
+
+	var list vacanciesResponse
+	if status := do(t, http.MethodPost, server.URL+"/api/refresh", "", &list); status != http.StatusOK {
+		t.Fatalf("refresh: unexpected status %d", status)
+	}
+	if len(list.Items) != 3 {
+		t.Fatalf("expected 3 vacancies, got %d", len(list.Items))
+	}
+
+	if status := do(t, http.MethodPost, server.URL+"/api/vacancies/1/apply", `{"message": "Hi"}`, nil); status != http.StatusOK {
+		t.Fatalf("apply: unexpected status %d", status)
+	}
+	if applied["1"] != "Hi" {
+		t.Fatalf("expected message to be passed, got %q", applied["1"])
+	}
+
+	var failure map[string]string
+	if status := do(t, http.MethodPost, server.URL+"/api/vacancies/3/apply", "", &failure); status != http.StatusBadGateway {
+		t.Fatalf("apply failure: unexpected status %d", status)
+	}
+	if !strings.Contains(failure["error"], "403") {
+		t.Fatalf("expected upstream error, got %v", failure)
+	}
+
+	if status := do(t, http.MethodPost, server.URL+"/api/vacancies/2/exclude", `{"reason": "no remote"}`, nil); status != http.StatusOK {
+		t.Fatalf("exclude: unexpected status %d", status)
+	}
+
+	if status := do(t, http.MethodPost, server.URL+"/api/vacancies/42/apply", "", nil); status != http.StatusNotFound {
+		t.Fatalf("unknown vacancy: unexpected status %d", status)
+	}
+
+	do(t, http.MethodGet, server.URL+"/api/vacancies", "", &list)
+	if len(list.Items) != 1 || list.Items[0].ID != "3" {
+		t.Fatalf("expected only the failed vacancy to stay, got %+v", list.Items)
+	}
+}
+
+func TestDashboardExcludeList(t *testing.T) {
+	server, httpServer, _ := newTestServer(t)
+	if err := server.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	do(t, http.MethodPost, httpServer.URL+"/api/vacancies/2/exclude", `{"reason": "no remote"}`, nil)
+
+	var excluded headhunter.ExcludedVacancies
+	do(t, http.MethodGet, httpServer.URL+"/api/excluded", "", &excluded)
+	if len(excluded.Items) != 1 || excluded.Items[0].Reason != "no remote" {
+		t.Fatalf("unexpected exclude list: %+v", excluded.Items)
+	}
+
+	if status := do(t, http.MethodDelete, httpServer.URL+"/api/excluded/2", "", nil); status != http.StatusOK {
+		t.Fatalf("remove: unexpected status %d", status)
+	}
+	if status := do(t, http.MethodDelete, httpServer.URL+"/api/excluded/2", "", nil); status != http.StatusNotFound {
+		t.Fatalf("second remove: unexpected status %d", status)
+	}
+
+	excluded = headhunter.ExcludedVacancies{}
+	do(t, http.MethodGet, httpServer.URL+"/api/excluded", "", &excluded)
+	if len(excluded.Items) != 0 {
+		t.Fatalf("expected empty exclude list, got %+v", excluded.Items)
+	}
+}
+
+func TestDashboardHistoryAndIndex(t *testing.T) {
+	_, server, _ := newTestServer(t)
+
+	var history struct {
+		Items []historyItem `json:"items"`
+	}
+	do(t, http.MethodGet, server.URL+"/api/history", "", &history)
+	if len(history.Items) != 1 || history.Items[0].VacancyName != "Old" {
+		t.Fatalf("unexpected history: %+v", history.Items)
+	}
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("unexpected index response: %s %s", resp.Status, resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestDashboardAuth(t *testing.T) {
+	_, server, applied := newTestServer(t)
+
+	// Redirects are checked by hand to see the cookie.
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	send := func(method, path string, header http.Header) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(context.Background(), method, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		return resp
+	}
+
+	if resp := send(http.MethodPost, "/api/refresh", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("request without token: unexpected status %s", resp.Status)
+	}
+	if resp := send(http.MethodGet, "/api/vacancies", http.Header{TokenHeader: {"wrong"}}); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("request with wrong token: unexpected status %s", resp.Status)
+	}
+
+	crossSite := []http.Header{
+		{TokenHeader: {testToken}, "Origin": {"https://evil.example"}},
+		{TokenHeader: {testToken}, "Origin": {"null"}},
+		{TokenHeader: {testToken}, "Sec-Fetch-Site": {"cross-site"}},
+	}
+	for _, header := range crossSite {
+		if resp := send(http.MethodPost, "/api/refresh", header); resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("cross-site request %v: unexpected status %s", header, resp.Status)
+		}
+	}
+
+	sameSite := http.Header{TokenHeader: {testToken}, "Origin": {server.URL}, "Sec-Fetch-Site": {"same-origin"}}
+	if resp := send(http.MethodPost, "/api/refresh", sameSite); resp.StatusCode != http.StatusOK {
+		t.Fatalf("same-origin request: unexpected status %s", resp.Status)
+	}
+
+	// The configured token is not handed out by the page.
+	if resp := send(http.MethodGet, "/", nil); len(resp.Cookies()) != 0 {
+		t.Fatalf("unexpected cookies: %v", resp.Cookies())
+	}
+	if resp := send(http.MethodGet, "/?token=wrong", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("page with wrong token: unexpected status %s", resp.Status)
+	}
+
+	resp := send(http.MethodGet, "/?token="+testToken, nil)
+	if resp.StatusCode != http.StatusSeeOther || len(resp.Cookies()) != 1 {
+		t.Fatalf("page with token: unexpected response %s %v", resp.Status, resp.Cookies())
+	}
+
+	cookie := http.Header{"Cookie": {resp.Cookies()[0].String()}}
+	if resp := send(http.MethodPost, "/api/vacancies/1/apply", cookie); resp.StatusCode != http.StatusOK {
+		t.Fatalf("request with cookie: unexpected status %s", resp.Status)
+	}
+	if _, ok := applied["1"]; !ok {
+		t.Fatal("expected the application to be sent")
+	}
+}
+
+func TestDashboardGeneratedToken(t *testing.T) {
+	server := New(&Deps{Logger: zap.NewNop()})
+	if server.token == "" || !server.shareToken {
+		t.Fatal("expected a generated token")
+	}
+
+	page := httptest.NewRecorder()
+	server.Handler().ServeHTTP(page, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080/", nil))
+
+	cookies := page.Result().Cookies()
+	if page.Code != http.StatusOK || len(cookies) != 1 || cookies[0].Value != server.token || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Fatalf("expected the page to set the token cookie, got %d %v", page.Code, cookies)
+	}
+
+	// A foreign host name resolved to the loopback must not get the token.
+	rebound := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rebound, httptest.NewRequest(http.MethodGet, "http://evil.example:8080/", nil))
+	if rebound.Code != http.StatusForbidden || len(rebound.Result().Cookies()) != 0 {
+		t.Fatalf("unexpected response to a foreign host: %d %v", rebound.Code, rebound.Result().Cookies())
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		"localhost":      true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+		"example.com":    false,
+	}
+
+	for address, expected := range tests {
+		if got := IsLoopback(address); got != expected {
+			t.Fatalf("%s: expected %v, got %v", address, expected, got)
+		}
+	}
+}
+
+func TestDashboardConcurrentApply(t *testing.T) {
+	var (
+		mu             sync.Mutex
+		calls, running int
+		maxRunning     int
+		started        = make(chan struct{}, 3)
+		release        = make(chan struct{})
+	)
+
+	server := New(&Deps{
+		Logger: zap.NewNop(),
+		Token:  testToken,
+		Collect: func(context.Context) (*headhunter.Vacancies, error) {
+			return &headhunter.Vacancies{Items: []*headhunter.Vacancy{{ID: "1"}, {ID: "2"}}}, nil
+		},
+		Apply: func(*headhunter.Vacancy, string) error {
+			mu.Lock()
+			calls++
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+
+			started <- struct{}{}
+			<-release
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		},
+	})
+	if err := server.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer := httptest.NewServer(server.Handler())
+	t.Cleanup(httpServer.Close)
+
+	statuses := make(chan int, 2)
+	for _, id := range []string{"1", "2"} {
+		go func() {
+			statuses <- do(t, http.MethodPost, httpServer.URL+"/api/vacancies/"+id+"/apply", "", nil)
+		}()
+	}
+
+	<-started
+
+	// The vacancy being applied to is not applied to again.
+	server.mu.Lock()
+	var busy string
+	for id := range server.inFlight {
+		busy = id
+	}
+	server.mu.Unlock()
+
+	if status := do(t, http.MethodPost, httpServer.URL+"/api/vacancies/"+busy+"/apply", "", nil); status != http.StatusConflict {
+		t.Fatalf("second apply: unexpected status %d", status)
+	}
+
+	close(release)
+	for range 2 {
+		if status := <-statuses; status != http.StatusOK {
+			t.Fatalf("apply: unexpected status %d", status)
+		}
+	}
+
+	if calls != 2 || maxRunning != 1 {
+		t.Fatalf("expected 2 sequential applications, got %d with %d at once", calls, maxRunning)
+	}
+}
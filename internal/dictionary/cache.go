@@ -0,0 +1,99 @@
+package dictionary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/utils"
+)
+
+// DefaultTTL is how long the cached dictionaries are used before loading them again.
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Cache keeps the dictionaries on disk between runs.
+type Cache struct {
+	Path string
+	TTL  time.Duration
+	// Locales to load, Locales by default. A cache loaded in other locales is fetched again.
+	Locales []string
+	// Source identifies the API and the site the dictionaries come from. A cache loaded from
+	// another source, e.g. a test server, is fetched again.
+	Source string
+}
+
+// DefaultCachePath returns the dictionaries file in the user cache directory.
+func DefaultCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "hh-responder", "dictionaries.json")
+}
+
+// Load returns the cached dictionaries or fetches them when the cache is missing, stale or refresh is set.
+func (c *Cache) Load(f Fetcher, refresh bool) (*Dictionary, error) {
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	locales := c.Locales
+	if len(locales) == 0 {
+		locales = Locales
+	}
+
+	if !refresh {
+		// A broken cache is fetched again like a missing one.
+		if cached, err := c.read(); err == nil && c.fresh(cached, ttl, locales) {
+			return cached, nil
+		}
+	}
+
+	dictionary, err := Fetch(f, locales...)
+	if err != nil {
+		return nil, err
+	}
+	dictionary.Source = c.Source
+
+	if err := c.write(dictionary); err != nil {
+		return nil, err
+	}
+
+	return dictionary, nil
+}
+
+func (c *Cache) fresh(cached *Dictionary, ttl time.Duration, locales []string) bool {
+	return time.Since(cached.FetchedAt) < ttl && slices.Equal(cached.Locales, locales) && cached.Source == c.Source
+}
+
+func (c *Cache) read() (*Dictionary, error) {
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var dictionary Dictionary
+	if err := json.Unmarshal(data, &dictionary); err != nil {
+		return nil, fmt.Errorf("decoding dictionaries cache %s: %w", c.Path, err)
+	}
+
+	return &dictionary, nil
+}
+
+func (c *Cache) write(dictionary *Dictionary) error {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return fmt.Errorf("creating dictionaries cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(dictionary)
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(c.Path, data, 0o644)
+}
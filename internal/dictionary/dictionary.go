@@ -0,0 +1,320 @@
+// Package dictionary resolves human-readable names of hh.ru areas, professional roles,
+// industries and other dictionaries to their ids.
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// Kinds of entries not coming from the flat /dictionaries.
+// Flat dictionaries keep their hh.ru names like schedule, employment or currency.
+const (
+	KindArea             = "area"
+	KindProfessionalRole = "professional_role"
+	KindIndustry         = "industry"
+)
+
+const maxSuggestions = 5
+
+// ErrNotLoaded is returned for kinds missing in the loaded dictionaries.
+var ErrNotLoaded = errors.New("dictionary is not loaded")
+
+// Locales are loaded together so names are accepted in both languages.
+var Locales = []string{"RU", "EN"}
+
+// LocalesWith returns Locales with the locale of the configured site added.
+func LocalesWith(locale string) []string {
+	locale = strings.ToUpper(strings.TrimSpace(locale))
+	if locale == "" || slices.Contains(Locales, locale) {
+		return Locales
+	}
+
+	return append(slices.Clone(Locales), locale)
+}
+
+// Entry is a dictionary value with its names in all loaded locales.
+type Entry struct {
+	Kind   string   `json:"kind"`
+	ID     string   `json:"id"`
+	Names  []string `json:"names"`
+	Parent string   `json:"parent,omitempty"`
+}
+
+type Dictionary struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Locales   []string  `json:"locales,omitempty"`
+	// Source is the API and the site of the cached dictionaries.
+	Source  string   `json:"source,omitempty"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Fetcher loads dictionaries from the API. It is implemented by headhunter.Client.
+type Fetcher interface {
+	GetAreas(locale string) ([]*headhunter.Area, error)
+	GetDictionaries(locale string) (map[string][]*headhunter.DictionaryItem, error)
+	GetProfessionalRoles(locale string) ([]*headhunter.Category, error)
+	GetIndustries(locale string) ([]*headhunter.Category, error)
+}
+
+// Fetch loads all dictionaries in the given locales, Locales by default.
+func Fetch(f Fetcher, locales ...string) (*Dictionary, error) {
+	if len(locales) == 0 {
+		locales = Locales
+	}
+
+	b := newBuilder()
+
+	for _, locale := range locales {
+		areas, err := f.GetAreas(locale)
+		if err != nil {
+			return nil, err
+		}
+		b.addAreas(areas, "")
+
+		dictionaries, err := f.GetDictionaries(locale)
+		if err != nil {
+			return nil, err
+		}
+		for kind, items := range dictionaries {
+			for _, item := range items {
+				id := item.ID
+				if id == "" {
+					id = item.Code
+				}
+				b.add(kind, id, item.Name, "")
+			}
+		}
+
+		roles, err := f.GetProfessionalRoles(locale)
+		if err != nil {
+			return nil, err
+		}
+		b.addCategories(KindProfessionalRole, roles, false)
+
+		industries, err := f.GetIndustries(locale)
+		if err != nil {
+			return nil, err
+		}
+		b.addCategories(KindIndustry, industries, true)
+	}
+
+	return &Dictionary{FetchedAt: time.Now().UTC(), Locales: locales, Entries: b.entries}, nil
+}
+
+type builder struct {
+	entries []*Entry
+	index   map[string]*Entry
+}
+
+func newBuilder() *builder {
+	return &builder{index: make(map[string]*Entry)}
+}
+
+// add appends the entry or adds another name to the existing one with the same kind and id.
+func (b *builder) add(kind, id, name, parent string) {
+	if id == "" {
+		return
+	}
+
+	key := kind + "/" + id
+	entry, ok := b.index[key]
+	if !ok {
+		entry = &Entry{Kind: kind, ID: id, Parent: parent}
+		b.index[key] = entry
+		b.entries = append(b.entries, entry)
+	}
+
+	name = strings.TrimSpace(name)
+	for _, existing := range entry.Names {
+		if existing == name {
+			return
+		}
+	}
+	if name != "" {
+		entry.Names = append(entry.Names, name)
+	}
+}
+
+func (b *builder) addAreas(areas []*headhunter.Area, parent string) {
+	for _, area := range areas {
+		b.add(KindArea, area.ID, area.Name, parent)
+		b.addAreas(area.Areas, area.Name)
+	}
+}
+
+// addCategories adds items of the categories. Industries can be chosen by the whole category too.
+func (b *builder) addCategories(kind string, categories []*headhunter.Category, withCategories bool) {
+	for _, category := range categories {
+		if withCategories {
+			b.add(kind, category.ID, category.Name, "")
+		}
+		for _, item := range category.Items {
+			b.add(kind, item.ID, item.Name, category.Name)
+		}
+	}
+}
+
+// Resolve returns the id of the value of the given kind. The value may be an id or a name in any locale.
+// Unknown values produce an error with the closest names.
+func (d *Dictionary) Resolve(kind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	var (
+		entries []*Entry
+		named   []*Entry
+	)
+	for _, entry := range d.Entries {
+		if entry.Kind != kind {
+			continue
+		}
+		if entry.ID == value {
+			return entry.ID, nil
+		}
+		entries = append(entries, entry)
+		if entry.hasName(value) {
+			named = append(named, entry)
+		}
+	}
+
+	switch {
+	case len(entries) == 0:
+		return "", fmt.Errorf("%s: %w", kind, ErrNotLoaded)
+	case len(named) == 1:
+		return named[0].ID, nil
+	case len(named) > 1:
+		return "", fmt.Errorf("%s %q is ambiguous, use one of the ids: %s", kind, value, describe(named))
+	}
+
+	if suggestions := suggest(entries, value); len(suggestions) > 0 {
+		return "", fmt.Errorf("%s %q not found, did you mean: %s?", kind, value, describe(suggestions))
+	}
+
+	return "", fmt.Errorf("%s %q not found", kind, value)
+}
+
+// Search returns entries whose id or any name contains the term, ignoring case.
+// An empty kind searches in all dictionaries.
+func (d *Dictionary) Search(term, kind string) []*Entry {
+	term = strings.ToLower(strings.TrimSpace(term))
+
+	var found []*Entry
+	for _, entry := range d.Entries {
+		if kind != "" && entry.Kind != kind {
+			continue
+		}
+		if strings.ToLower(entry.ID) == term || entry.contains(term) {
+			found = append(found, entry)
+		}
+	}
+
+	return found
+}
+
+// Name returns the first name of the entry or its id.
+func (e *Entry) Name() string {
+	if len(e.Names) == 0 {
+		return e.ID
+	}
+	return strings.Join(e.Names, " / ")
+}
+
+func (e *Entry) hasName(name string) bool {
+	for _, n := range e.Names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Entry) contains(term string) bool {
+	for _, n := range e.Names {
+		if strings.Contains(strings.ToLower(n), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns entries with names close to the value: within a few edits or starting with it.
+func suggest(entries []*Entry, value string) []*Entry {
+	value = strings.ToLower(value)
+	maxDistance := max(2, len([]rune(value))/4)
+
+	type candidate struct {
+		entry    *Entry
+		distance int
+	}
+
+	var candidates []candidate
+	for _, entry := range entries {
+		best := -1
+		for _, name := range entry.Names {
+			name = strings.ToLower(name)
+			distance := levenshtein(value, name)
+			if strings.HasPrefix(name, value) {
+				distance = min(distance, 1)
+			}
+			if best == -1 || distance < best {
+				best = distance
+			}
+		}
+		if best != -1 && best <= maxDistance {
+			candidates = append(candidates, candidate{entry: entry, distance: best})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	result := make([]*Entry, 0, min(len(candidates), maxSuggestions))
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		result = append(result, c.entry)
+	}
+
+	return result
+}
+
+func describe(entries []*Entry) string {
+	parts := make([]string, 0, len(entries))
+	for _, entry := range entries[:min(len(entries), maxSuggestions)] {
+		part := fmt.Sprintf("%s (%s", entry.Name(), entry.ID)
+		if entry.Parent != "" {
+			part += ", " + entry.Parent
+		}
+		parts = append(parts, part+")")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,142 @@
+package dictionary
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+type fakeFetcher struct {
+	calls int
+}
+
+func (f *fakeFetcher) GetAreas(locale string) ([]*headhunter.Area, error) {
+	f.calls++
+	names := map[string][]string{
+		"RU": {"Россия", "Москва", "Грузия", "Тбилиси", "Александровка", "Александровка"},
+		"EN": {"Russia", "Moscow", "Georgia", "Tbilisi", "Aleksandrovka", "Aleksandrovka"},
+		"KZ": {"Ресей", "Мәскеу", "Грузия", "Тбилиси", "Александровка", "Александровка"},
+	}[locale]
+
+	return []*headhunter.Area{
+		{ID: "113", Name: names[0], Areas: []*headhunter.Area{
+			{ID: "1", Name: names[1]},
+			{ID: "501", Name: names[4]},
+			{ID: "502", Name: names[5]},
+		}},
+		{ID: "28", Name: names[2], Areas: []*headhunter.Area{{ID: "2758", Name: names[3]}}},
+	}, nil
+}
+
+func (f *fakeFetcher) GetDictionaries(locale string) (map[string][]*headhunter.DictionaryItem, error) {
+	name := map[string]string{"RU": "Удаленная работа", "EN": "Remote working"}[locale]
+	return map[string][]*headhunter.DictionaryItem{
+		"schedule": {{ID: "remote", Name: name}, {ID: "fullDay", Name: "Full day"}},
+		"currency": {{Code: "RUR", Name: "Рубли"}},
+	}, nil
+}
+
+func (f *fakeFetcher) GetProfessionalRoles(string) ([]*headhunter.Category, error) {
+	return []*headhunter.Category{{ID: "11", Name: "IT", Items: []*headhunter.DictionaryItem{{ID: "96", Name: "Программист, разработчик"}}}}, nil
+}
+
+func (f *fakeFetcher) GetIndustries(string) ([]*headhunter.Category, error) {
+	return []*headhunter.Category{{ID: "7", Name: "IT", Items: []*headhunter.DictionaryItem{{ID: "7.540", Name: "Разработка ПО"}}}}, nil
+}
+
+func TestResolve(t *testing.T) {
+	dictionary, err := Fetch(&fakeFetcher{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		kind   string
+		value  string
+		expect string
+		err    string
+	}{
+		{kind: KindArea, value: "113", expect: "113"},
+		{kind: KindArea, value: "Moscow", expect: "1"},
+		{kind: KindArea, value: "москва", expect: "1"},
+		{kind: KindArea, value: " Tbilisi ", expect: "2758"},
+		{kind: KindArea, value: "Tbilsi", err: `did you mean: Тбилиси / Tbilisi (2758, Грузия)?`},
+		{kind: KindArea, value: "Aleksandrovka", err: "ambiguous"},
+		{kind: KindArea, value: "Atlantis", err: `area "Atlantis" not found`},
+		{kind: "schedule", value: "Remote working", expect: "remote"},
+		{kind: "schedule", value: "fullDay", expect: "fullDay"},
+		{kind: "currency", value: "Рубли", expect: "RUR"},
+		{kind: KindProfessionalRole, value: "программист, разработчик", expect: "96"},
+		{kind: KindIndustry, value: "IT", expect: "7"},
+		{kind: "metro", value: "1.2", err: ErrNotLoaded.Error()},
+	}
+
+	for _, tt := range tests {
+		got, err := dictionary.Resolve(tt.kind, tt.value)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("%s %q: expected error %q, got %v", tt.kind, tt.value, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s %q: unexpected error: %v", tt.kind, tt.value, err)
+		}
+		if got != tt.expect {
+			t.Fatalf("%s %q: expected %s, got %s", tt.kind, tt.value, tt.expect, got)
+		}
+	}
+
+	if found := dictionary.Search("tbil", ""); len(found) != 1 || found[0].ID != "2758" {
+		t.Fatalf("unexpected search result: %+v", found)
+	}
+	if found := dictionary.Search("IT", KindIndustry); len(found) != 1 {
+		t.Fatalf("unexpected search result: %+v", found)
+	}
+}
+
+func TestCacheLoad(t *testing.T) {
+	fetcher := &fakeFetcher{}
+	cache := &Cache{Path: filepath.Join(t.TempDir(), "cache", "dictionaries.json")}
+
+	for _, refresh := range []bool{false, false, true} {
+		if _, err := cache.Load(fetcher, refresh); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Areas are fetched once per locale: on the first load and on the refresh.
+	if fetcher.calls != 2*len(Locales) {
+		t.Fatalf("expected %d fetches, got %d", 2*len(Locales), fetcher.calls)
+	}
+
+	// The site locale is added to the default ones and the cache is fetched again.
+	fetcher.calls = 0
+	cache.Locales = LocalesWith("kz")
+	for range 2 {
+		if _, err := cache.Load(fetcher, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if fetcher.calls != 3 || len(LocalesWith("en")) != len(Locales) {
+		t.Fatalf("expected 3 fetches for locales %v, got %d", cache.Locales, fetcher.calls)
+	}
+
+	// Dictionaries of another API or site are fetched again.
+	fetcher.calls = 0
+	cache.Locales = nil
+	for _, source := range []string{"https://api.hh.ru hh.kz", "https://api.hh.ru hh.kz", "http://127.0.0.1:8080 hh.kz"} {
+		cache.Source = source
+		if _, err := cache.Load(fetcher, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if fetcher.calls != 2*len(Locales) {
+		t.Fatalf("expected %d fetches for changed sources, got %d", 2*len(Locales), fetcher.calls)
+	}
+}
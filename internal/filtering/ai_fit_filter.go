@@ -20,10 +20,11 @@ type aiFitFilter struct {
 }
 
 type AIFitFilterDeps struct {
-	Logger      *zap.Logger
-	HH          *headhunter.Client
-	Matcher     ai.Matcher
-	Resume      *headhunter.Resume
+	Logger  *zap.Logger
+	HH      *headhunter.Client
+	Matcher ai.Matcher
+	// Resumes are compared with every vacancy. The best scoring one is stored in the assessment.
+	Resumes     []*headhunter.Resume
 	ExcludeFile string
 }
 
@@ -57,11 +58,11 @@ func (f *aiFitFilter) Disable(reason string) {
 	f.reason = reason
 }
 
-func (f *aiFitFilter) WithDeps(client *headhunter.Client, matcher *gemini.Matcher, resume *headhunter.Resume, logger *zap.Logger) {
+func (f *aiFitFilter) WithDeps(client *headhunter.Client, matcher *gemini.Matcher, resumes []*headhunter.Resume, logger *zap.Logger) {
 	f.deps.HH = client
 	f.deps.Matcher = matcher
 	f.deps.Logger = logger
-	f.deps.Resume = resume
+	f.deps.Resumes = resumes
 }
 
 func (f *aiFitFilter) IsEnabled() bool { return f.enabled }
@@ -71,6 +72,19 @@ func (f *aiFitFilter) Validate() error {
 		return fmt.Errorf("deps are not initialized: filter is not usable")
 	}
 
+	if len(f.deps.Resumes) == 0 {
+		return fmt.Errorf("at least one resume is required")
+	}
+
+	if f.deps.Matcher == nil {
+		return fmt.Errorf("ai matcher is required")
+	}
+
+	// The fake provider is configured by its script only.
+	if strings.EqualFold(strings.TrimSpace(f.config.Provider), ai.ProviderFake) {
+		return nil
+	}
+
 	if f.config.Gemini == nil {
 		return fmt.Errorf("gemini configuration is required when ai filter is enabled")
 	}
@@ -83,22 +97,39 @@ func (f *aiFitFilter) Validate() error {
 func (f *aiFitFilter) Apply(ctx context.Context, v *headhunter.Vacancies) (*headhunter.Vacancies, Step, error) {
 	initial := v.Len()
 
-	resumeDetails, err := f.deps.HH.GetResumeRaw(f.deps.Resume.ID)
-	if err != nil {
-		return v, Step{}, fmt.Errorf("get resume details: %w", err)
+	resumes := make([]resumePayload, 0, len(f.deps.Resumes))
+	for _, resume := range f.deps.Resumes {
+		details, err := f.deps.HH.GetResumeRaw(resume.ID)
+		if err != nil {
+			return v, Step{}, fmt.Errorf("get resume %q details: %w", resume.Title, err)
+		}
+		resumes = append(resumes, resumePayload{resume: resume, details: details})
 	}
 
-	f.applyMatcher(ctx, resumeDetails, v)
+	decisions := f.applyMatcher(ctx, resumes, v)
 
 	left := v.Len()
-	return v, Step{Initial: initial, Dropped: initial - left, Left: left}, nil
+	return v, Step{Initial: initial, Dropped: initial - left, Left: left, Decisions: decisions}, nil
 }
 
-func (f *aiFitFilter) applyMatcher(ctx context.Context, resume map[string]any, vacancies *headhunter.Vacancies) {
+type resumePayload struct {
+	resume  *headhunter.Resume
+	details map[string]any
+}
+
+func (f *aiFitFilter) applyMatcher(ctx context.Context, resumes []resumePayload, vacancies *headhunter.Vacancies) []Decision {
 	initial := vacancies.Len()
 	approved := make([]*headhunter.Vacancy, 0, initial)
+	var decisions []Decision
 
 	for _, vacancy := range vacancies.Items {
+		// Vacancies carried over from a previous run keep their assessment.
+		if vacancy.AI != nil && vacancy.AI.Error == "" && vacancy.AI.Fit {
+			f.deps.Logger.Debug("vacancy is already approved by AI", zap.String("vacancy_id", vacancy.ID))
+			approved = append(approved, vacancy)
+			continue
+		}
+
 		detailed := vacancy
 		full, err := f.deps.HH.GetVacancy(vacancy.ID)
 		if err != nil {
@@ -106,12 +137,13 @@ func (f *aiFitFilter) applyMatcher(ctx context.Context, resume map[string]any, v
 				zap.String("vacancy_id", vacancy.ID),
 				zap.Error(err),
 			)
+			decisions = append(decisions, drop(vacancy, "fetching detailed vacancy failed: %s", err))
 			continue
 		}
 
 		detailed = full
 
-		assessment, err := f.deps.Matcher.Evaluate(ctx, resume, detailed)
+		assessment, resume, err := f.evaluate(ctx, resumes, detailed)
 		if err != nil {
 			f.deps.Logger.Warn("AI evaluation failed",
 				zap.String("vacancy_id", vacancy.ID),
@@ -123,11 +155,13 @@ func (f *aiFitFilter) applyMatcher(ctx context.Context, resume map[string]any, v
 		}
 
 		detailed.AI = &headhunter.AIAssessment{
-			Fit:     assessment.Fit,
-			Score:   assessment.Score,
-			Reason:  assessment.Reason,
-			Message: assessment.Message,
-			Raw:     assessment.Raw,
+			Fit:         assessment.Fit,
+			Score:       assessment.Score,
+			Reason:      assessment.Reason,
+			Message:     assessment.Message,
+			Raw:         assessment.Raw,
+			ResumeID:    resume.ID,
+			ResumeTitle: resume.Title,
 		}
 
 		if !detailed.AI.Fit {
@@ -137,6 +171,8 @@ func (f *aiFitFilter) applyMatcher(ctx context.Context, resume map[string]any, v
 				zap.String("reason", assessment.Reason),
 			)
 
+			decisions = append(decisions, f.rejection(detailed))
+
 			if err := f.appendToExcludeFile(detailed, assessment.Reason); err != nil {
 				f.deps.Logger.Warn("failed to append vacancy to exclude file",
 					zap.String("vacancy_id", vacancy.ID),
@@ -149,6 +185,7 @@ func (f *aiFitFilter) applyMatcher(ctx context.Context, resume map[string]any, v
 		f.deps.Logger.Info("vacancy approved by AI",
 			zap.String("vacancy_id", vacancy.ID),
 			zap.Float64("ai_score", assessment.Score),
+			zap.String("resume", resume.Title),
 		)
 
 		approved = append(approved, detailed)
@@ -160,6 +197,72 @@ func (f *aiFitFilter) applyMatcher(ctx context.Context, resume map[string]any, v
 		zap.Int("initial_vacancies", initial),
 		zap.Int("approved_vacancies", len(approved)),
 	)
+
+	return decisions
+}
+
+// evaluate compares the vacancy with every resume and returns the best assessment.
+// A fit assessment beats an unfit one, then the higher score wins. Errors are returned only when all resumes failed.
+func (f *aiFitFilter) evaluate(ctx context.Context, resumes []resumePayload, vacancy *headhunter.Vacancy) (*ai.FitAssessment, *headhunter.Resume, error) {
+	var (
+		best       *ai.FitAssessment
+		bestResume *headhunter.Resume
+		lastErr    error
+	)
+
+	for _, payload := range resumes {
+		assessment, err := f.deps.Matcher.Evaluate(ctx, payload.details, vacancy)
+		if err != nil {
+			lastErr = err
+			if len(resumes) > 1 {
+				f.deps.Logger.Warn("AI evaluation against resume failed",
+					zap.String("vacancy_id", vacancy.ID),
+					zap.String("resume", payload.resume.Title),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+
+		if len(resumes) > 1 {
+			f.deps.Logger.Debug("vacancy evaluated against resume",
+				zap.String("vacancy_id", vacancy.ID),
+				zap.String("resume", payload.resume.Title),
+				zap.Bool("fit", assessment.Fit),
+				zap.Float64("ai_score", assessment.Score),
+			)
+		}
+
+		if best == nil || better(assessment, best) {
+			best, bestResume = assessment, payload.resume
+		}
+	}
+
+	if best == nil {
+		return nil, nil, lastErr
+	}
+
+	return best, bestResume, nil
+}
+
+func better(a, b *ai.FitAssessment) bool {
+	if a.Fit != b.Fit {
+		return a.Fit
+	}
+	return a.Score > b.Score
+}
+
+// rejection explains why the AI provider rejected the vacancy.
+func (f *aiFitFilter) rejection(vacancy *headhunter.Vacancy) Decision {
+	if vacancy.AI.Score < f.config.MinimumFitScore {
+		return drop(vacancy, "AI score %.2f < %.2f", vacancy.AI.Score, f.config.MinimumFitScore)
+	}
+
+	if vacancy.AI.Reason == "" {
+		return drop(vacancy, "AI marked vacancy as not fit (score %.2f)", vacancy.AI.Score)
+	}
+
+	return drop(vacancy, "AI marked vacancy as not fit (score %.2f): %s", vacancy.AI.Score, vacancy.AI.Reason)
 }
 
 func (f *aiFitFilter) appendToExcludeFile(vacancy *headhunter.Vacancy, reason string) error {
@@ -168,16 +271,13 @@ func (f *aiFitFilter) appendToExcludeFile(vacancy *headhunter.Vacancy, reason st
 		return nil
 	}
 
-	excluded, err := headhunter.GetExludedVacanciesFromFile(path)
-	if err != nil {
-		return fmt.Errorf("load excluded vacancies: %w", err)
-	}
-
 	toAppend := (&headhunter.Vacancies{Items: []*headhunter.Vacancy{vacancy}}).ToExcluded(headhunter.ExcludeActorAI, reason)
-	excluded.Append(toAppend)
-
-	if err := excluded.ToFile(path); err != nil {
-		return fmt.Errorf("write excluded vacancies: %w", err)
+	_, err := headhunter.UpdateExcludedFile(path, func(excluded *headhunter.ExcludedVacancies) error {
+		excluded.Append(toAppend)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update excluded vacancies: %w", err)
 	}
 
 	f.deps.Logger.Info("vacancy appended to exclude file",
@@ -0,0 +1,98 @@
+package filtering
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/ai"
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// scoreMatcher scores a vacancy by the resume title and the vacancy id.
+type scoreMatcher map[string]*ai.FitAssessment
+
+func (m scoreMatcher) Evaluate(_ context.Context, resume map[string]any, vacancy *headhunter.Vacancy) (*ai.FitAssessment, error) {
+	assessment, ok := m[resume["title"].(string)+"/"+vacancy.ID]
+	if !ok {
+		return nil, errors.New("quota exceeded")
+	}
+	return assessment, nil
+}
+
+func TestAIFitPicksBestResume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/resumes/"):
+			id := strings.TrimPrefix(r.URL.Path, "/resumes/")
+			_, _ = w.Write([]byte(`{"id": "` + id + `", "title": "` + id + `"}`))
+		case strings.HasPrefix(r.URL.Path, headhunter.SearchPath+"/"):
+			id := strings.TrimPrefix(r.URL.Path, headhunter.SearchPath+"/")
+			_, _ = w.Write([]byte(`{"id": "` + id + `", "name": "vacancy ` + id + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := headhunter.New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+
+	matcher := scoreMatcher{
+		"devops/1": {Fit: true, Score: 0.7},
+		"golang/1": {Fit: true, Score: 0.9, Message: "go letter"},
+		"devops/2": {Fit: true, Score: 0.65},
+		"golang/2": {Fit: false, Score: 0.95},
+		"devops/3": {Fit: false, Score: 0.2},
+		"golang/3": {Fit: false, Score: 0.3},
+		"golang/4": {Fit: true, Score: 0.8},
+	}
+
+	filter := NewAIFit(&AIFitFilterConfig{Enabled: true, MinimumFitScore: 0.6, Gemini: &AIGeminiConfig{Model: "test"}}, &AIFitFilterDeps{
+		Logger:  zap.NewNop(),
+		HH:      client,
+		Matcher: matcher,
+		Resumes: []*headhunter.Resume{{ID: "devops", Title: "DevOps"}, {ID: "golang", Title: "Go"}},
+	})
+
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	// Vacancy 5 is carried over from a previous run and is not evaluated again.
+	carried := &headhunter.Vacancy{ID: "5", AI: &headhunter.AIAssessment{Fit: true, Score: 0.75, Message: "carried letter", ResumeID: "golang"}}
+	vacancies := &headhunter.Vacancies{Items: []*headhunter.Vacancy{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, carried}}
+
+	left, step, err := filter.Apply(context.Background(), vacancies)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"1": "golang", "2": "devops", "4": "golang", "5": "golang"}
+	if left.Len() != len(expected) {
+		t.Fatalf("unexpected vacancies left: %v", headhunter.IDs(left.Items))
+	}
+
+	for _, vacancy := range left.Items {
+		if vacancy.AI.ResumeID != expected[vacancy.ID] {
+			t.Fatalf("vacancy %s: expected resume %s, got %s", vacancy.ID, expected[vacancy.ID], vacancy.AI.ResumeID)
+		}
+	}
+
+	if left.Items[0].AI.Message != "go letter" || left.Items[0].AI.ResumeTitle != "Go" {
+		t.Fatalf("unexpected assessment: %+v", left.Items[0].AI)
+	}
+
+	if carried.AI.Message != "carried letter" || carried.AI.Error != "" {
+		t.Fatalf("carried assessment must be kept: %+v", carried.AI)
+	}
+
+	if len(step.Decisions) != 1 || step.Decisions[0].Reason != "AI score 0.30 < 0.60" {
+		t.Fatalf("unexpected decisions: %+v", step.Decisions)
+	}
+}
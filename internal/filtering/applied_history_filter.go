@@ -71,10 +71,15 @@ func (f *appliedHistoryFilter) Apply(_ context.Context, v *headhunter.Vacancies)
 	excluded := v.Exclude(headhunter.VacancyIDField, negotiations.VacanciesIDs())
 	if len(excluded) > 0 {
 		f.deps.Logger.Info("excluding vacancies based on my negotiations",
-			zap.Strings("excluded_vacancies", excluded),
+			zap.Strings("excluded_vacancies", headhunter.IDs(excluded)),
 			zap.Int("vacancies_left", v.Len()),
 		)
 	}
 
-	return v, Step{Initial: initial, Dropped: len(excluded), Left: v.Len()}, nil
+	return v, Step{
+		Initial:   initial,
+		Dropped:   len(excluded),
+		Left:      v.Len(),
+		Decisions: dropAll(excluded, "already applied: vacancy found in negotiations"),
+	}, nil
 }
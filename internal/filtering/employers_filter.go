@@ -33,5 +33,10 @@ func (f *employersFilter) Apply(_ context.Context, v *headhunter.Vacancies) (*he
 
 	excluded := v.Exclude(headhunter.VacancyEmployerIDField, f.employers)
 
-	return v, Step{Initial: initial, Dropped: len(excluded), Left: v.Len()}, nil
+	decisions := make([]Decision, 0, len(excluded))
+	for _, vacancy := range excluded {
+		decisions = append(decisions, drop(vacancy, "employer %s in exclude list", vacancy.Employer.ID))
+	}
+
+	return v, Step{Initial: initial, Dropped: len(excluded), Left: v.Len(), Decisions: decisions}, nil
 }
@@ -40,5 +40,14 @@ func (f *excludeFileFilter) Apply(_ context.Context, v *headhunter.Vacancies) (*
 	ids := excluded.VacanciesIDs()
 	removed := v.Exclude(headhunter.VacancyIDField, ids)
 
-	return v, Step{Initial: initial, Dropped: len(removed), Left: v.Len()}, nil
+	decisions := make([]Decision, 0, len(removed))
+	for _, vacancy := range removed {
+		decision := drop(vacancy, "vacancy %s in exclude file", vacancy.ID)
+		if entry := excluded.Find(vacancy.ID); entry != nil && entry.Reason != "" {
+			decision.Reason += fmt.Sprintf(" (%s: %s)", entry.Actor, entry.Reason)
+		}
+		decisions = append(decisions, decision)
+	}
+
+	return v, Step{Initial: initial, Dropped: len(removed), Left: v.Len(), Decisions: decisions}, nil
 }
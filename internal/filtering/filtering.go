@@ -19,8 +19,9 @@ type Filter interface {
 }
 
 type Filtering struct {
-	steps  []Filter
-	logger *zap.Logger
+	steps   []Filter
+	logger  *zap.Logger
+	results []StepResult
 }
 
 // Step describes the result of executing a filtering step.
@@ -28,6 +29,35 @@ type Step struct {
 	Initial int
 	Dropped int
 	Left    int
+	// Decisions explain why vacancies were dropped. Vacancies dropped without a decision get a generic one.
+	Decisions []Decision
+}
+
+// StepResult is the outcome of a filter during the last run.
+type StepResult struct {
+	Name    string
+	Enabled bool
+	Step
+}
+
+// Decision records why a filter dropped a vacancy.
+type Decision struct {
+	Vacancy *headhunter.Vacancy
+	Filter  string
+	Reason  string
+}
+
+// drop creates a decision for the vacancy. The filter name is filled in by RunFilters.
+func drop(vacancy *headhunter.Vacancy, format string, args ...any) Decision {
+	return Decision{Vacancy: vacancy, Reason: fmt.Sprintf(format, args...)}
+}
+
+func dropAll(vacancies []*headhunter.Vacancy, format string, args ...any) []Decision {
+	decisions := make([]Decision, 0, len(vacancies))
+	for _, vacancy := range vacancies {
+		decisions = append(decisions, drop(vacancy, format, args...))
+	}
+	return decisions
 }
 
 func New(filters []Filter, logger *zap.Logger) *Filtering {
@@ -56,28 +86,38 @@ func (f *Filtering) DisableByName(name, reason string) error {
 	return nil
 }
 
-// Run executes the supplied filters sequentially, returning the resulting vacancies list and AI assessments.
-func (f *Filtering) RunFilters(ctx context.Context, vacancies *headhunter.Vacancies) (*headhunter.Vacancies, error) {
+// RunFilters executes the supplied filters sequentially, returning the resulting vacancies list
+// and the decision log for every dropped vacancy.
+func (f *Filtering) RunFilters(ctx context.Context, vacancies *headhunter.Vacancies) (*headhunter.Vacancies, []Decision, error) {
 	for _, step := range f.steps {
 		if !step.IsEnabled() {
 			continue
 		}
 		if err := step.Validate(); err != nil {
-			return nil, fmt.Errorf("%s: %w", step.Name(), err)
+			return nil, nil, fmt.Errorf("%s: %w", step.Name(), err)
 		}
 	}
 
+	f.results = make([]StepResult, 0, len(f.steps))
+	var decisions []Decision
+
 	for _, step := range f.steps {
 		if !step.IsEnabled() {
 			f.logger.Info("filter disabled", zap.String("name", step.Name()))
+			f.results = append(f.results, StepResult{Name: step.Name()})
 			continue
 		}
 
+		before := append([]*headhunter.Vacancy{}, vacancies.Items...)
+
 		next, info, err := step.Apply(ctx, vacancies)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %w", step.Name(), err)
+			return nil, nil, fmt.Errorf("%s: %w", step.Name(), err)
 		}
 
+		f.results = append(f.results, StepResult{Name: step.Name(), Enabled: true, Step: info})
+		decisions = append(decisions, attribute(step.Name(), before, next, info.Decisions)...)
+
 		f.logger.Info("filter step",
 			zap.String("name", step.Name()),
 			zap.Int("initial", info.Initial),
@@ -88,5 +128,34 @@ func (f *Filtering) RunFilters(ctx context.Context, vacancies *headhunter.Vacanc
 		vacancies = next
 	}
 
-	return vacancies, nil
+	return vacancies, decisions, nil
+}
+
+// Steps returns results of filters executed by the last RunFilters call.
+func (f *Filtering) Steps() []StepResult {
+	return f.results
+}
+
+// attribute returns decisions for every vacancy dropped by the filter.
+func attribute(name string, before []*headhunter.Vacancy, after *headhunter.Vacancies, decisions []Decision) []Decision {
+	left := make(map[string]bool, after.Len())
+	for _, vacancy := range after.Items {
+		left[vacancy.ID] = true
+	}
+
+	explained := make(map[string]bool, len(decisions))
+	result := make([]Decision, 0, len(decisions))
+	for _, decision := range decisions {
+		decision.Filter = name
+		explained[decision.Vacancy.ID] = true
+		result = append(result, decision)
+	}
+
+	for _, vacancy := range before {
+		if !left[vacancy.ID] && !explained[vacancy.ID] {
+			result = append(result, Decision{Vacancy: vacancy, Filter: name, Reason: "dropped by " + name})
+		}
+	}
+
+	return result
 }
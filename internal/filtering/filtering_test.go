@@ -0,0 +1,69 @@
+package filtering
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// silentFilter drops the first vacancy without explaining why.
+type silentFilter struct{}
+
+func (silentFilter) Name() string    { return "silent" }
+func (silentFilter) Disable(string)  {}
+func (silentFilter) IsEnabled() bool { return true }
+func (silentFilter) Validate() error { return nil }
+func (silentFilter) Apply(_ context.Context, v *headhunter.Vacancies) (*headhunter.Vacancies, Step, error) {
+	initial := v.Len()
+	v.Items = v.Items[1:]
+	return v, Step{Initial: initial, Dropped: 1, Left: v.Len()}, nil
+}
+
+func TestRunFiltersDecisions(t *testing.T) {
+	items := make([]*headhunter.Vacancy, 0, 4)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		items = append(items, &headhunter.Vacancy{ID: id})
+	}
+	items[0].Employer.ID = "3331116"
+	items[2].HasTest = true
+
+	filters := New([]Filter{
+		NewExludedEmployers([]string{"3331116"}),
+		NewWithTest(),
+		silentFilter{},
+	}, zap.NewNop())
+
+	left, decisions, err := filters.RunFilters(context.Background(), &headhunter.Vacancies{Items: items})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if left.Len() != 1 || left.Items[0].ID != "4" {
+		t.Fatalf("unexpected vacancies left: %v", headhunter.IDs(left.Items))
+	}
+
+	expected := []Decision{
+		{Filter: "employers", Reason: "employer 3331116 in exclude list"},
+		{Filter: "with_test", Reason: "vacancy requires a test"},
+		{Filter: "silent", Reason: "dropped by silent"},
+	}
+	expectedIDs := []string{"1", "3", "2"}
+
+	if len(decisions) != len(expected) {
+		t.Fatalf("expected %d decisions, got %+v", len(expected), decisions)
+	}
+
+	for idx, decision := range decisions {
+		if decision.Vacancy.ID != expectedIDs[idx] || decision.Filter != expected[idx].Filter || decision.Reason != expected[idx].Reason {
+			t.Fatalf("decision %d: unexpected %s/%s/%s", idx, decision.Vacancy.ID, decision.Filter, decision.Reason)
+		}
+	}
+
+	steps := filters.Steps()
+	if len(steps) != 3 || steps[1].Dropped != 1 || steps[2].Left != 1 {
+		t.Fatalf("unexpected steps: %+v", steps)
+	}
+}
@@ -25,5 +25,10 @@ func (f *withTestFilter) Apply(_ context.Context, v *headhunter.Vacancies) (*hea
 	initial := v.Len()
 	excluded := v.ExcludeWithTest()
 
-	return v, Step{Initial: initial, Dropped: len(excluded), Left: v.Len()}, nil
+	return v, Step{
+		Initial:   initial,
+		Dropped:   len(excluded),
+		Left:      v.Len(),
+		Decisions: dropAll(excluded, "vacancy requires a test"),
+	}, nil
 }
@@ -0,0 +1,77 @@
+package headhunter
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// Cluster is a distribution of search results by one parameter, e.g. area or salary.
+type Cluster struct {
+	ID    string         `json:"id"`
+	Name  string         `json:"name"`
+	Items []*ClusterItem `json:"items"`
+}
+
+// ClusterItem is a value of the cluster. URL is the search narrowed to the value.
+type ClusterItem struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Count int    `json:"count"`
+}
+
+// ClusterResult is the number of found vacancies with their clusters.
+type ClusterResult struct {
+	Found    int
+	Clusters []*Cluster
+}
+
+// SearchClusters returns the distribution of the search results without fetching all pages.
+func (c *Client) SearchClusters(params *SearchParams) (*ClusterResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid search parameters: %w", err)
+	}
+
+	q := buildParams(params)
+	q.Set("clusters", "true")
+	q.Set("per_page", "1")
+
+	var response Page[struct{}]
+	if err := c.getJSON(c.APIURL+SearchPath, q, &response); err != nil {
+		return nil, err
+	}
+
+	return &ClusterResult{Found: response.Found, Clusters: response.Clusters}, nil
+}
+
+// Refinement returns the query parameters with the values which narrow the search to the item.
+func (i *ClusterItem) Refinement(params *SearchParams) (url.Values, error) {
+	u, err := url.Parse(i.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cluster url: %w", err)
+	}
+
+	base := buildParams(params)
+	refinement := url.Values{}
+	for key, values := range u.Query() {
+		if key == "clusters" || key == "per_page" || key == "page" {
+			continue
+		}
+
+		var added []string
+		for _, value := range values {
+			if !slices.Contains(base[key], value) {
+				added = append(added, value)
+			}
+		}
+		if len(added) > 0 {
+			refinement[key] = added
+		}
+	}
+
+	if len(refinement) == 0 {
+		return nil, fmt.Errorf("cluster item %q does not change the search", i.Name)
+	}
+
+	return refinement, nil
+}
@@ -0,0 +1,107 @@
+package headhunter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Area is a node of the hh.ru areas tree: a country, a region or a city.
+type Area struct {
+	ID       string  `json:"id"`
+	ParentID string  `json:"parent_id"`
+	Name     string  `json:"name"`
+	Areas    []*Area `json:"areas"`
+}
+
+// DictionaryItem is an entry of a flat dictionary. Currencies have a code instead of the id.
+type DictionaryItem struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Category groups professional roles or industries.
+type Category struct {
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	Items []*DictionaryItem `json:"items"`
+}
+
+// GetAreas returns the areas tree with names in the given locale (RU or EN).
+func (c *Client) GetAreas(locale string) ([]*Area, error) {
+	var areas []*Area
+	if err := c.getJSON(c.APIURL+"/areas", localeQuery(locale), &areas); err != nil {
+		return nil, fmt.Errorf("getting areas: %w", err)
+	}
+
+	return areas, nil
+}
+
+// GetDictionaries returns the flat dictionaries like schedule, employment or experience by their names.
+// Values which are not lists of items are skipped.
+func (c *Client) GetDictionaries(locale string) (map[string][]*DictionaryItem, error) {
+	var raw map[string]json.RawMessage
+	if err := c.getJSON(c.APIURL+"/dictionaries", localeQuery(locale), &raw); err != nil {
+		return nil, fmt.Errorf("getting dictionaries: %w", err)
+	}
+
+	dictionaries := make(map[string][]*DictionaryItem, len(raw))
+	for name, value := range raw {
+		var items []*DictionaryItem
+		if err := json.Unmarshal(value, &items); err != nil {
+			continue
+		}
+		dictionaries[name] = items
+	}
+
+	return dictionaries, nil
+}
+
+// GetProfessionalRoles returns professional roles grouped by categories.
+func (c *Client) GetProfessionalRoles(locale string) ([]*Category, error) {
+	var response struct {
+		Categories []struct {
+			ID    string            `json:"id"`
+			Name  string            `json:"name"`
+			Roles []*DictionaryItem `json:"roles"`
+		} `json:"categories"`
+	}
+	if err := c.getJSON(c.APIURL+"/professional_roles", localeQuery(locale), &response); err != nil {
+		return nil, fmt.Errorf("getting professional roles: %w", err)
+	}
+
+	categories := make([]*Category, 0, len(response.Categories))
+	for _, category := range response.Categories {
+		categories = append(categories, &Category{ID: category.ID, Name: category.Name, Items: category.Roles})
+	}
+
+	return categories, nil
+}
+
+// GetIndustries returns industries grouped by categories.
+func (c *Client) GetIndustries(locale string) ([]*Category, error) {
+	var response []struct {
+		ID         string            `json:"id"`
+		Name       string            `json:"name"`
+		Industries []*DictionaryItem `json:"industries"`
+	}
+	if err := c.getJSON(c.APIURL+"/industries", localeQuery(locale), &response); err != nil {
+		return nil, fmt.Errorf("getting industries: %w", err)
+	}
+
+	categories := make([]*Category, 0, len(response))
+	for _, category := range response {
+		categories = append(categories, &Category{ID: category.ID, Name: category.Name, Items: category.Industries})
+	}
+
+	return categories, nil
+}
+
+func localeQuery(locale string) url.Values {
+	if locale == "" {
+		return nil
+	}
+
+	return url.Values{"locale": {locale}}
+}
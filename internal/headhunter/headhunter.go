@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,16 +16,54 @@ const (
 	userAgent   = "spigell/hh-responder (spigelly@gmail.com)"
 	// Max value for search per page.
 	perPage = "100"
+	// DefaultHost is the site the API serves when no host is set.
+	DefaultHost = "hh.ru"
+	// DefaultLanguage is the language of sites missing in SiteLanguages.
+	DefaultLanguage = "Russian"
 )
 
+// SiteLanguages are the HeadHunter sites served by the API with their main languages.
+var SiteLanguages = map[string]string{
+	"hh.ru":         "Russian",
+	"rabota.by":     "Russian",
+	"hh.kz":         "Russian",
+	"hh.uz":         "Russian",
+	"headhunter.kg": "Russian",
+	"hh1.az":        "Azerbaijani",
+	"headhunter.ge": "Georgian",
+}
+
+// SiteLanguage returns the main language of the site.
+func SiteLanguage(host string) string {
+	if language, ok := SiteLanguages[host]; ok {
+		return language
+	}
+	return DefaultLanguage
+}
+
+// TokenRefresher returns a new access token to replace the rejected one.
+type TokenRefresher func(ctx context.Context, rejected string) (string, error)
+
 type Client struct {
 	// ctx used only for http requests right now
-	ctx        context.Context
-	token      string
-	logger     *zap.Logger
-	HTTPClient *http.Client
-	UserAgent  string
-	APIURL     string
+	ctx     context.Context
+	token   string
+	refresh TokenRefresher
+	tokenMu sync.Mutex
+	// interval is the minimal pause between requests. Zero means no limit.
+	interval    time.Duration
+	throttleMu  sync.Mutex
+	lastRequest time.Time
+	logger      *zap.Logger
+	HTTPClient  *http.Client
+	UserAgent   string
+	APIURL      string
+	// Host is the site to work with, e.g. hh.kz. The API serves hh.ru when it is empty.
+	Host string
+	// Locale is the language of names in responses, e.g. RU or EN. Requests may set their own.
+	Locale string
+	// MaxBodySize limits decoded responses, DefaultMaxBodySize when zero.
+	MaxBodySize int64
 }
 
 func New(ctx context.Context, token string, logger *zap.Logger) *Client {
@@ -40,6 +79,20 @@ func New(ctx context.Context, token string, logger *zap.Logger) *Client {
 	}
 }
 
+// SetTokenRefresher enables a single retry with a refreshed token for requests rejected with 401.
+func (c *Client) SetTokenRefresher(refresh TokenRefresher) {
+	c.refresh = refresh
+}
+
+// SetRateLimit limits the client to the given number of requests per second. Zero disables the limit.
+func (c *Client) SetRateLimit(requestsPerSecond float64) {
+	if requestsPerSecond <= 0 {
+		c.interval = 0
+		return
+	}
+	c.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+}
+
 func (c *Client) Search(params *SearchParams) (*Vacancies, error) {
 	return c.search(params)
 }
@@ -0,0 +1,389 @@
+// Package hhtest provides a fake hh.ru API server driven by recorded JSON fixtures,
+// so the client and the whole run pipeline can be tested offline.
+//
+// Fixtures are laid out like the API paths:
+//
+//	vacancies.json          search results, an array of vacancies
+//	vacancies/{id}.json     full vacancy, the search item is served when missing
+//	resumes/mine.json       an array of mine resumes
+//	resumes/{id}.json       full resume
+//	negotiations.json       an array of negotiations
+//
+// Lists are paginated like hh.ru does: page and per_page parameters, found, pages
+// and no more than MaxDepth results reachable for a query.
+package hhtest
+
+import (
+	"compress/gzip"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxDepth is the number of results hh.ru returns for a single query regardless of found.
+	MaxDepth = 2000
+
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+//go:embed fixtures
+var recorded embed.FS
+
+// Application is a negotiation created with POST /negotiations.
+type Application struct {
+	ResumeID  string
+	VacancyID string
+	Message   string
+}
+
+// Server emulates the hh.ru API endpoints used by the client.
+type Server struct {
+	*httptest.Server
+
+	fixtures fs.FS
+
+	mu           sync.Mutex
+	gzip         bool
+	failures     map[string]int
+	requests     []string
+	applications []Application
+	// limit is the number of applications accepted before limit_exceeded. Zero means no limit.
+	limit int
+}
+
+// NewServer starts a server with the fixtures recorded in the package.
+func NewServer() *Server {
+	fixtures, _ := fs.Sub(recorded, "fixtures")
+	return NewServerFS(fixtures)
+}
+
+// NewServerFS starts a server with custom fixtures.
+func NewServerFS(fixtures fs.FS) *Server {
+	s := &Server{
+		fixtures: fixtures,
+		gzip:     true,
+		failures: make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vacancies", s.listHandler("vacancies.json"))
+	mux.HandleFunc("GET /vacancies/{id}", s.getVacancy)
+	mux.HandleFunc("GET /resumes/mine", s.listHandler("resumes/mine.json"))
+	mux.HandleFunc("GET /resumes/{id}", s.objectHandler("resumes"))
+	mux.HandleFunc("GET /negotiations", s.getNegotiations)
+	mux.HandleFunc("POST /negotiations", s.postNegotiation)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+
+	return s
+}
+
+// SetGzip toggles compression of responses. hh.ru compresses them, so it is on by default.
+func (s *Server) SetGzip(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.gzip = enabled
+}
+
+// Fail makes requests to the path fail with the status. Zero status removes the failure.
+func (s *Server) Fail(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if status == 0 {
+		delete(s.failures, path)
+		return
+	}
+	s.failures[path] = status
+}
+
+// LimitApplications makes hh.ru reject applications over the number with limit_exceeded,
+// like it does when the daily limit is reached. Zero removes the limit.
+func (s *Server) LimitApplications(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limit = n
+}
+
+// Requests returns the method and the URI of every request served.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// Applications returns the negotiations created during the test.
+func (s *Server) Applications() []Application {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Application(nil), s.applications...)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		status, failed := s.failures[r.URL.Path]
+		compress := s.gzip && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+		s.mu.Unlock()
+
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			writeError(w, http.StatusForbidden, "oauth", "bad_authorization")
+			return
+		}
+
+		if failed {
+			writeError(w, status, "fake", "failure injected by the test")
+			return
+		}
+
+		if compress {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			w = &gzipWriter{ResponseWriter: w, writer: gz}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) listHandler(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var items []json.RawMessage
+		if err := s.load(name, &items); err != nil {
+			writeError(w, http.StatusInternalServerError, "fixture", err.Error())
+			return
+		}
+
+		writePage(w, r, items)
+	}
+}
+
+func (s *Server) objectHandler(dir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var object json.RawMessage
+		err := s.load(path.Join(dir, r.PathValue("id")+".json"), &object)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			writeError(w, http.StatusNotFound, "not_found", "")
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, "fixture", err.Error())
+		default:
+			writeJSON(w, http.StatusOK, object)
+		}
+	}
+}
+
+// getVacancy serves the full vacancy or the search item when the full one is not recorded.
+func (s *Server) getVacancy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var vacancy json.RawMessage
+	err := s.load(path.Join("vacancies", id+".json"), &vacancy)
+	if errors.Is(err, fs.ErrNotExist) {
+		vacancy, err = s.findVacancy(id)
+	}
+
+	switch {
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "fixture", err.Error())
+	case vacancy == nil:
+		writeError(w, http.StatusNotFound, "not_found", "")
+	default:
+		writeJSON(w, http.StatusOK, vacancy)
+	}
+}
+
+// getNegotiations serves the recorded negotiations followed by the ones created during the test.
+func (s *Server) getNegotiations(w http.ResponseWriter, r *http.Request) {
+	var items []json.RawMessage
+	if err := s.load("negotiations.json", &items); err != nil {
+		writeError(w, http.StatusInternalServerError, "fixture", err.Error())
+		return
+	}
+
+	for idx, application := range s.Applications() {
+		vacancy, err := s.findVacancy(application.VacancyID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "fixture", err.Error())
+			return
+		}
+
+		now := time.Now().Format("2006-01-02T15:04:05-0700")
+		item, _ := json.Marshal(map[string]any{
+			"id":         fmt.Sprintf("created-%d", idx+1),
+			"state":      map[string]string{"id": "response", "name": "Отклик"},
+			"created_at": now,
+			"updated_at": now,
+			"vacancy":    vacancy,
+		})
+		items = append(items, item)
+	}
+
+	writePage(w, r, items)
+}
+
+// postNegotiation records the application like hh.ru: the resume must be one of mine
+// and a vacancy can be applied to once.
+func (s *Server) postNegotiation(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_argument", err.Error())
+		return
+	}
+
+	application := Application{
+		ResumeID:  r.FormValue("resume_id"),
+		VacancyID: r.FormValue("vacancy_id"),
+		Message:   r.FormValue("message"),
+	}
+
+	vacancy, err := s.findVacancy(application.VacancyID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "fixture", err.Error())
+		return
+	}
+	if vacancy == nil {
+		writeError(w, http.StatusNotFound, "not_found", "")
+		return
+	}
+
+	var resumes []struct {
+		ID string `json:"id"`
+	}
+	if err := s.load("resumes/mine.json", &resumes); err != nil {
+		writeError(w, http.StatusInternalServerError, "fixture", err.Error())
+		return
+	}
+
+	known := false
+	for _, resume := range resumes {
+		known = known || resume.ID == application.ResumeID
+	}
+	if !known {
+		writeError(w, http.StatusBadRequest, "negotiations", "invalid_vacancy_or_resume")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, applied := range s.applications {
+		if applied.VacancyID == application.VacancyID {
+			writeError(w, http.StatusForbidden, "negotiations", "already_applied")
+			return
+		}
+	}
+
+	if s.limit > 0 && len(s.applications) >= s.limit {
+		writeError(w, http.StatusForbidden, "negotiations", "limit_exceeded")
+		return
+	}
+
+	s.applications = append(s.applications, application)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// findVacancy returns the search item of the vacancy or nil when it is not recorded.
+func (s *Server) findVacancy(id string) (json.RawMessage, error) {
+	var items []json.RawMessage
+	if err := s.load("vacancies.json", &items); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		var vacancy struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(item, &vacancy); err != nil {
+			return nil, err
+		}
+		if vacancy.ID == id {
+			return item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) load(name string, target any) error {
+	data, err := fs.ReadFile(s.fixtures, name)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("decoding fixture %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func writePage(w http.ResponseWriter, r *http.Request, items []json.RawMessage) {
+	q := r.URL.Query()
+
+	perPage := defaultPerPage
+	if v := q.Get("per_page"); v != "" {
+		perPage, _ = strconv.Atoi(v)
+	}
+	page, _ := strconv.Atoi(q.Get("page"))
+
+	if perPage <= 0 || perPage > maxPerPage || page < 0 {
+		writeError(w, http.StatusBadRequest, "bad_argument", "per_page")
+		return
+	}
+
+	reachable := min(len(items), MaxDepth)
+	if page > 0 && page*perPage >= MaxDepth {
+		writeError(w, http.StatusBadRequest, "bad_argument", "page")
+		return
+	}
+
+	start, end := min(page*perPage, reachable), min((page+1)*perPage, reachable)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":    items[start:end],
+		"found":    len(items),
+		"pages":    (reachable + perPage - 1) / perPage,
+		"page":     page,
+		"per_page": perPage,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError responds with the error body of hh.ru.
+func writeError(w http.ResponseWriter, status int, kind, value string) {
+	writeJSON(w, status, map[string]any{
+		"errors":     []map[string]string{{"type": kind, "value": value}},
+		"request_id": "hhtest",
+	})
+}
+
+type gzipWriter struct {
+	http.ResponseWriter
+	writer *gzip.Writer
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	return w.writer.Write(data)
+}
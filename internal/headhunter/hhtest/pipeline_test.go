@@ -0,0 +1,145 @@
+package hhtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/ai"
+	"github.com/spigell/hh-responder/internal/ai/fake"
+	"github.com/spigell/hh-responder/internal/filtering"
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// TestPipeline goes through the steps of run: search, filtering, vacancy details and applying.
+func TestPipeline(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	client := headhunter.New(context.Background(), "token", logger)
+	client.APIURL = server.URL
+
+	resumes, err := client.GetMineResumes()
+	if err != nil {
+		t.Fatalf("getting mine resumes: %v", err)
+	}
+
+	resume := resumes.FindByTitle("Go Developer")
+	if resume == nil || !resume.IsPublished() {
+		t.Fatalf("unexpected resumes: %v", resumes.Titles())
+	}
+
+	collect := func() *headhunter.Vacancies {
+		vacancies, err := client.Search(&headhunter.SearchParams{Text: "go", PerPage: "10"})
+		if err != nil {
+			t.Fatalf("search: %v", err)
+		}
+
+		filters := filtering.New([]filtering.Filter{
+			filtering.NewWithTest(),
+			filtering.NewAppliedHistory(nil, &filtering.AppliedHistoryDeps{HH: client, Logger: logger}),
+			filtering.NewExludedEmployers([]string{"3331116"}),
+		}, logger)
+
+		filtered, _, err := filters.RunFilters(context.Background(), vacancies)
+		if err != nil {
+			t.Fatalf("filtering: %v", err)
+		}
+
+		return filtered
+	}
+
+	// 25 recorded vacancies: 2 with a test, 2 already applied and 4 of the excluded employer.
+	vacancies := collect()
+	if vacancies.Len() != 17 {
+		t.Fatalf("expected 17 vacancies after filtering, got %d: %v", vacancies.Len(), headhunter.IDs(vacancies.Items))
+	}
+
+	searched := 0
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, "GET /vacancies?") {
+			searched++
+		}
+	}
+	if searched != 3 {
+		t.Fatalf("expected 3 search pages requested, got %d", searched)
+	}
+
+	details, err := client.GetVacancy(vacancies.Items[0].ID)
+	if err != nil {
+		t.Fatalf("getting vacancy: %v", err)
+	}
+	if details.PlainDescription() == "" || len(details.KeySkillNames()) != 3 {
+		t.Fatalf("expected full vacancy, got %+v", details)
+	}
+
+	if err := client.Apply(resume, vacancies, "Hello!"); err != nil {
+		t.Fatalf("applying: %v", err)
+	}
+
+	applications := server.Applications()
+	if len(applications) != vacancies.Len() || applications[0].ResumeID != resume.ID || applications[0].Message != "Hello!" {
+		t.Fatalf("unexpected applications: %+v", applications)
+	}
+
+	// The next run finds the same vacancies in the negotiations.
+	if left := collect(); left.Len() != 0 {
+		t.Fatalf("expected nothing to apply on the second run, got %v", headhunter.IDs(left.Items))
+	}
+}
+
+// TestPipelineWithFakeAI runs the ai_fit filter with the scripted provider.
+func TestPipelineWithFakeAI(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	logger := zap.NewNop()
+	client := headhunter.New(context.Background(), "token", logger)
+	client.APIURL = server.URL
+
+	resumes, err := client.GetMineResumes()
+	if err != nil {
+		t.Fatalf("getting mine resumes: %v", err)
+	}
+
+	vacancies, err := client.Search(&headhunter.SearchParams{Text: "go"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+
+	// Kubernetes is mentioned by both recorded full vacancies and the name of one more.
+	script := &fake.Script{
+		Default: &fake.Assessment{Fit: false, Score: 0.2, Reason: "not a Go role"},
+		Keywords: []*fake.KeywordRule{
+			{Keyword: "kubernetes", Assessment: fake.Assessment{Fit: true, Score: 0.9, Message: "Hello!"}},
+			{Keyword: "go", Assessment: fake.Assessment{Fit: true, Score: 0.5}},
+		},
+	}
+
+	filter := filtering.NewAIFit(&filtering.AIFitFilterConfig{Enabled: true, Provider: ai.ProviderFake, MinimumFitScore: 0.6}, &filtering.AIFitFilterDeps{
+		Logger:  logger,
+		HH:      client,
+		Matcher: fake.NewMatcher(script, 0.6, logger),
+		Resumes: []*headhunter.Resume{resumes.FindByTitle("Go Developer")},
+	})
+
+	filtered, decisions, err := filtering.New([]filtering.Filter{filter}, logger).RunFilters(context.Background(), vacancies)
+	if err != nil {
+		t.Fatalf("filtering: %v", err)
+	}
+
+	if ids := headhunter.IDs(filtered.Items); strings.Join(ids, ",") != "100000001,100000003,100000007" {
+		t.Fatalf("unexpected vacancies approved: %v", ids)
+	}
+
+	if filtered.Items[0].AI.Message != "Hello!" || filtered.Items[0].AI.ResumeTitle != "Go Developer" {
+		t.Fatalf("unexpected assessment %+v", filtered.Items[0].AI)
+	}
+
+	if len(decisions) != 22 {
+		t.Fatalf("expected 22 rejected vacancies, got %d", len(decisions))
+	}
+}
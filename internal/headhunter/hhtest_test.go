@@ -0,0 +1,143 @@
+package headhunter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter/hhtest"
+)
+
+func TestClientWithFixtures(t *testing.T) {
+	server := hhtest.NewServer()
+	defer server.Close()
+
+	client := New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+
+	tests := []struct {
+		name  string
+		setup func()
+		call  func() error
+		err   string
+	}{
+		{
+			name: "search pages",
+			call: func() error {
+				vacancies, err := client.Search(&SearchParams{Text: "go", PerPage: "10"})
+				if err == nil && vacancies.Len() != 25 {
+					t.Fatalf("expected 25 vacancies, got %d", vacancies.Len())
+				}
+				return err
+			},
+		},
+		{
+			name:  "plain responses",
+			setup: func() { server.SetGzip(false) },
+			call: func() error {
+				negotiations, err := client.GetNegotiations()
+				if err == nil && len(*negotiations) != 2 {
+					t.Fatalf("expected 2 negotiations, got %d", len(*negotiations))
+				}
+				return err
+			},
+		},
+		{
+			name: "search item without recorded details",
+			call: func() error {
+				vacancy, err := client.GetVacancy("100000002")
+				if err == nil && vacancy.Employer.Name != "Сбер" {
+					t.Fatalf("unexpected vacancy %+v", vacancy)
+				}
+				return err
+			},
+		},
+		{
+			name: "raw resume",
+			call: func() error {
+				raw, err := client.GetResumeRaw("a1b2c3d4e5f60000000000000000000000000001")
+				if err == nil && !strings.Contains(ResumeText(raw), "Key skills:\nGo, Kubernetes, PostgreSQL, gRPC") {
+					t.Fatalf("unexpected resume text:\n%s", ResumeText(raw))
+				}
+				return err
+			},
+		},
+		{
+			name: "missing vacancy",
+			call: func() error { _, err := client.GetVacancy("1"); return err },
+			err:  "404 Not Found",
+		},
+		{
+			name:  "server error",
+			setup: func() { server.Fail("/resumes/mine", http.StatusServiceUnavailable) },
+			call:  func() error { _, err := client.GetMineResumes(); return err },
+			err:   "503 Service Unavailable",
+		},
+		{
+			name: "unknown resume",
+			call: func() error {
+				return client.ApplyWithMessage(&Resume{ID: "unknown"}, &Vacancy{ID: "100000001"}, "")
+			},
+			err: "400 Bad Request",
+		},
+		{
+			name: "applied twice",
+			setup: func() {
+				resume := &Resume{ID: "a1b2c3d4e5f60000000000000000000000000001"}
+				if err := client.ApplyWithMessage(resume, &Vacancy{ID: "100000005"}, ""); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+			call: func() error {
+				return client.ApplyWithMessage(&Resume{ID: "a1b2c3d4e5f60000000000000000000000000001"}, &Vacancy{ID: "100000005"}, "")
+			},
+			err: "403 Forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			err := tt.call()
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestApplyLimitExceeded(t *testing.T) {
+	server := hhtest.NewServer()
+	defer server.Close()
+	server.LimitApplications(1)
+
+	client := New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+
+	resume := &Resume{ID: "a1b2c3d4e5f60000000000000000000000000001"}
+	if err := client.ApplyWithMessage(resume, &Vacancy{ID: "100000001"}, "Hello!"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := client.ApplyWithMessage(resume, &Vacancy{ID: "100000003"}, "Hello!")
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+
+	// Other rejections are not mistaken for the limit.
+	server.LimitApplications(0)
+	err = client.ApplyWithMessage(resume, &Vacancy{ID: "100000001"}, "Hello!")
+	if err == nil || errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected already applied error, got %v", err)
+	}
+}
@@ -1,10 +1,9 @@
 package headhunter
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
-
-	"github.com/mitchellh/mapstructure"
 )
 
 const (
@@ -12,13 +11,21 @@ const (
 	allStatusesExceptArchived = "non_archived"
 )
 
+// ErrLimitExceeded is returned when hh.ru rejects the application for the daily limit of negotiations.
+var ErrLimitExceeded = errors.New("daily limit of applications is exceeded")
+
 type Negotations []*Negotiation
 
 type Negotiation struct {
 	ID        string
-	CreatedAt string `json:"created_at" mapstructure:"created_at"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
 	URL       string
-	Vacancy   *Vacancy
+	State     struct {
+		ID   string
+		Name string
+	}
+	Vacancy *Vacancy
 }
 
 type NegotiationResponse struct {
@@ -34,16 +41,12 @@ func (c *Client) GetNegotiations() (*Negotations, error) {
 	// Set per_page max as possible. It should be faster.
 	q.Add("per_page", perPage)
 
-	items, err := c.GetItems(apiURLMineNegotations, q, 0)
+	list, err := collect(items[*Negotiation](c.ctx, c, apiURLMineNegotations, q))
 	if err != nil {
 		return nil, err
 	}
 
-	var negotations Negotations
-	if err = mapstructure.Decode(items, &negotations); err != nil {
-		return nil, err
-	}
-
+	negotations := Negotations(list)
 	return &negotations, nil
 }
 
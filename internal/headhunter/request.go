@@ -2,17 +2,23 @@ package headhunter
 
 import (
 	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/utils"
 )
 
 const (
@@ -20,100 +26,80 @@ const (
 	contentEncoding = "gzip, deflate, br"
 )
 
-type ItemResponse struct {
-	Items   []Item
-	Found   int
-	Pages   int
-	Page    int
+// Page is a page of a list returned by hh.ru.
+type Page[T any] struct {
+	Items   []T `json:"items"`
+	Found   int `json:"found"`
+	Pages   int `json:"pages"`
+	Page    int `json:"page"`
 	PerPage int `json:"per_page"`
+	// Clusters are returned only when requested with the clusters parameter.
+	Clusters []*Cluster `json:"clusters"`
 }
 
-type Item any
-
-// GetItems makes GET request to HeadHunter API and returns items honoring the optional limit.
-func (c *Client) GetItems(url string, q url.Values, limit int) ([]Item, error) {
-	var items []Item
-
-	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req = c.setHeaders(req)
-	// Additional headers. For GET requests only
-	req.Header.Set("Content-Type", contentType)
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := c.request(req)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := c.parseItemResponse(resp)
-	if err != nil {
-		return nil, err
-	}
+// pages requests pages of the list one by one and decodes them into T. It stops after the last page,
+// on the first error, when the context is cancelled or when the consumer stops.
+func pages[T any](ctx context.Context, c *Client, apiURL string, q url.Values) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		query := url.Values{}
+		for key, values := range q {
+			query[key] = values
+		}
 
-	c.logger.Debug("got response from HH.ru", zap.Int("pages", response.Pages), zap.Int("max items per page", response.PerPage))
+		for page := 0; ; page++ {
+			if page > 0 {
+				query.Set("page", strconv.Itoa(page))
+			}
 
-	items = append(items, response.Items...)
-	if limit > 0 && len(items) >= limit {
-		return items[:limit], nil
-	}
+			var response Page[T]
+			if err := c.fetchJSON(ctx, apiURL, query, &response); err != nil {
+				yield(nil, err)
+				return
+			}
 
-	for response.Page < (response.Pages - 1) {
-		if limit > 0 && len(items) >= limit {
-			break
-		}
-		c.logger.Debug("additional request neeeded", zap.String("reason", fmt.Sprintf(
-			"current page (%d) < all page count (%d)", response.Page+1, response.Pages),
-		))
+			c.logger.Debug("got response from HH.ru", zap.Int("page", response.Page), zap.Int("pages", response.Pages), zap.Int("max items per page", response.PerPage))
 
-		resp, err = c.request(addPage(req, response.Page+1))
-		if err != nil {
-			return nil, err
-		}
+			if !yield(&response, nil) || response.Page >= response.Pages-1 {
+				return
+			}
 
-		response, err = c.parseItemResponse(resp)
-		if err != nil {
-			return nil, err
-		}
-
-		items = append(items, response.Items...)
-		if limit > 0 && len(items) >= limit {
-			items = items[:limit]
-			break
+			c.logger.Debug("additional request neeeded", zap.String("reason", fmt.Sprintf(
+				"current page (%d) < all page count (%d)", response.Page+1, response.Pages),
+			))
 		}
 	}
-
-	return items, nil
 }
 
-func (c *Client) parseItemResponse(resp *http.Response) (*ItemResponse, error) {
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status: %s", resp.Status)
+// items yields items of all pages of the list.
+func items[T any](ctx context.Context, c *Client, apiURL string, q url.Values) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range pages[T](ctx, c, apiURL, q) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
 	}
+}
 
-	var body io.ReadCloser
-	var err error
-	switch resp.Header.Get("Content-Encoding") {
-	case "gzip":
-		body, err = gzip.NewReader(resp.Body)
+// collect returns all items of the sequence or the first error.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var result []T
+	for item, err := range seq {
 		if err != nil {
 			return nil, err
 		}
-		defer body.Close()
-	default:
-		body = resp.Body
-		defer body.Close()
-	}
-
-	var response *ItemResponse
-	if err := json.NewDecoder(body).Decode(&response); err != nil {
-		return nil, err
+		result = append(result, item)
 	}
 
-	return response, nil
+	return result, nil
 }
 
 func (c *Client) postFormData(url string, data map[string]string) error {
@@ -144,27 +130,174 @@ func (c *Client) postFormData(url string, data map[string]string) error {
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+
+	body, err := c.readBody(resp)
+	if err != nil {
+		return err
+	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("bad status: %s", resp.Status)
+		return statusError(resp, body)
 	}
 
 	return nil
 }
 
+// apiErrors is the error response of hh.ru.
+type apiErrors struct {
+	Errors []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"errors"`
+}
+
+// StatusError is returned for responses with an unexpected status.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return "bad status: " + e.Status
+}
+
+// IsTemporary reports whether the request may succeed when repeated later: network errors,
+// rate limiting and server errors.
+func IsTemporary(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == http.StatusTooManyRequests || statusErr.Code >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// statusError describes the failed response recognizing errors callers may handle.
+func statusError(resp *http.Response, body []byte) error {
+	var response apiErrors
+	if json.Unmarshal(body, &response) == nil {
+		for _, e := range response.Errors {
+			if e.Value == "limit_exceeded" {
+				return fmt.Errorf("%w: %s", ErrLimitExceeded, resp.Status)
+			}
+		}
+	}
+
+	return &StatusError{Code: resp.StatusCode, Status: resp.Status}
+}
+
 func (c *Client) request(req *http.Request) (*http.Response, error) {
 	c.logger.Debug("make request", zap.String("url", req.URL.String()))
+
+	// The token may be refreshed since the request was prepared, e.g. between pages.
+	c.setAuthorization(req)
+	c.setSite(req)
+
+	if err := c.throttle(req.Context()); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
+	if resp.StatusCode != http.StatusUnauthorized || c.refresh == nil {
+		return resp, nil
+	}
+
+	retry, err := c.refreshRequest(req)
+	if err != nil {
+		c.logger.Warn("token refresh failed", zap.Error(err))
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	c.logger.Debug("retry request with refreshed token", zap.String("url", req.URL.String()))
+
+	return c.HTTPClient.Do(retry)
+}
+
+// throttle waits until the next request is allowed by the rate limit.
+func (c *Client) throttle(ctx context.Context) error {
+	if c.interval == 0 {
+		return nil
+	}
+
+	c.throttleMu.Lock()
+	defer c.throttleMu.Unlock()
+
+	if err := utils.WaitFor(ctx, time.Until(c.lastRequest.Add(c.interval))); err != nil {
+		return err
+	}
+
+	c.lastRequest = time.Now()
+	return nil
+}
+
+// refreshRequest refreshes the token and returns a copy of the request to retry.
+func (c *Client) refreshRequest(req *http.Request) (*http.Request, error) {
+	if req.Body != nil && req.GetBody == nil {
+		return nil, fmt.Errorf("request body can't be replayed")
+	}
+
+	rejected := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+
+	c.tokenMu.Lock()
+	token, err := c.refresh(req.Context(), rejected)
+	if err == nil {
+		c.token = token
+	}
+	c.tokenMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	return retry, nil
+}
+
+func (c *Client) setAuthorization(req *http.Request) {
+	c.tokenMu.Lock()
+	token := c.token
+	c.tokenMu.Unlock()
+
+	// Public endpoints like dictionaries are requested without a token.
+	if token == "" {
+		req.Header.Del("Authorization")
+		return
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+}
+
+// setSite adds the host and locale parameters hh.ru uses to pick the site and the language of names.
+func (c *Client) setSite(req *http.Request) {
+	if c.Host == "" && c.Locale == "" {
+		return
+	}
+
+	q := req.URL.Query()
+	if c.Host != "" && !q.Has("host") {
+		q.Set("host", c.Host)
+	}
+	if c.Locale != "" && !q.Has("locale") {
+		q.Set("locale", c.Locale)
+	}
+	req.URL.RawQuery = q.Encode()
 }
 
 func (c *Client) setHeaders(req *http.Request) *http.Request {
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	c.setAuthorization(req)
 	req.Header.Set("User-Agent", c.UserAgent)
 	req.Header.Set("Accept-Encoding", contentEncoding)
 
@@ -172,7 +305,12 @@ func (c *Client) setHeaders(req *http.Request) *http.Request {
 }
 
 func (c *Client) getJSON(url string, q url.Values, target any) error {
-	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
+	return c.fetchJSON(c.ctx, url, q, target)
+}
+
+// fetchJSON makes GET request bound to the context and decodes the response into the target.
+func (c *Client) fetchJSON(ctx context.Context, url string, q url.Values, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return err
 	}
@@ -187,26 +325,14 @@ func (c *Client) getJSON(url string, q url.Values, target any) error {
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	var reader io.Reader = resp.Body
-	var gzipReader *gzip.Reader
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		gzipReader, err = gzip.NewReader(resp.Body)
-		if err != nil {
-			return err
-		}
-		defer gzipReader.Close()
-		reader = gzipReader
-	}
 
-	data, err := io.ReadAll(reader)
+	data, err := c.readBody(resp)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
+		return &StatusError{Code: resp.StatusCode, Status: resp.Status}
 	}
 
 	if target == nil {
@@ -214,17 +340,8 @@ func (c *Client) getJSON(url string, q url.Values, target any) error {
 	}
 
 	if err := json.Unmarshal(data, target); err != nil {
-		return err
+		return fmt.Errorf("decoding response of %s: %w", req.URL.Path, err)
 	}
 
 	return nil
 }
-
-// addPage adds page parameter to request URL.
-func addPage(req *http.Request, page int) *http.Request {
-	q := req.URL.Query()
-	q.Set("page", strconv.Itoa(page))
-	req.URL.RawQuery = q.Encode()
-
-	return req
-}
@@ -0,0 +1,147 @@
+package headhunter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRequestRefreshesTokenOnUnauthorized(t *testing.T) {
+	var authorizations []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			if err := r.ParseMultipartForm(1 << 20); err != nil || r.FormValue("vacancy_id") != "42" {
+				t.Errorf("body was not replayed: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"items": [{"id": "1", "title": "Go"}], "pages": 1}`))
+	}))
+	defer server.Close()
+
+	client := New(context.Background(), "stale", zap.NewNop())
+	client.APIURL = server.URL
+
+	refreshes := 0
+	client.SetTokenRefresher(func(_ context.Context, rejected string) (string, error) {
+		refreshes++
+		if rejected != "stale" {
+			t.Errorf("unexpected rejected token %q", rejected)
+		}
+		return "fresh", nil
+	})
+
+	resumes, err := client.GetMineResumes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resumes.Len() != 1 {
+		t.Fatalf("expected a resume, got %d", resumes.Len())
+	}
+
+	if err := client.ApplyWithMessage(&Resume{ID: "r"}, &Vacancy{ID: "42"}, "hi"); err != nil {
+		t.Fatalf("unexpected apply error: %v", err)
+	}
+
+	if refreshes != 1 {
+		t.Fatalf("expected a single refresh, got %d", refreshes)
+	}
+	if len(authorizations) != 3 || authorizations[2] != "Bearer fresh" {
+		t.Fatalf("unexpected authorizations: %v", authorizations)
+	}
+}
+
+func TestRequestWithoutRefresherReturnsUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := New(context.Background(), "stale", zap.NewNop())
+	client.APIURL = server.URL
+
+	if _, err := client.GetMineResumes(); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	client.SetTokenRefresher(func(context.Context, string) (string, error) {
+		return "", errors.New("refresh token is revoked")
+	})
+
+	if _, err := client.GetMineResumes(); err == nil {
+		t.Fatal("expected an error when refresh fails")
+	}
+}
+
+func TestRequestSetsSite(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+	client.Host = "hh.kz"
+	client.Locale = "KZ"
+
+	if _, err := client.GetAreas("EN"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.ApplyWithMessage(&Resume{ID: "r"}, &Vacancy{ID: "v"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The locale of the request wins over the client one.
+	expected := []string{"host=hh.kz&locale=EN", "host=hh.kz&locale=KZ"}
+	if len(queries) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(queries))
+	}
+	for i, q := range queries {
+		if q.Encode() != expected[i] {
+			t.Fatalf("request %d: expected %s, got %s", i, expected[i], q.Encode())
+		}
+	}
+}
+
+func TestIsTemporary(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{err: &StatusError{Code: http.StatusServiceUnavailable}, expected: true},
+		{err: fmt.Errorf("applying: %w", &StatusError{Code: http.StatusTooManyRequests}), expected: true},
+		{err: &StatusError{Code: http.StatusForbidden}},
+		{err: fmt.Errorf("%w: 403 Forbidden", ErrLimitExceeded)},
+		{err: &url.Error{Op: "Post", URL: "https://api.hh.ru/negotiations", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, expected: true},
+		{err: errors.New("resume is required")},
+	}
+
+	for _, tt := range tests {
+		if got := IsTemporary(tt.err); got != tt.expected {
+			t.Fatalf("%v: expected %v, got %v", tt.err, tt.expected, got)
+		}
+	}
+}
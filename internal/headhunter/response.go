@@ -0,0 +1,43 @@
+package headhunter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spigell/hh-responder/internal/httpbody"
+)
+
+// DefaultMaxBodySize limits decoded responses. The largest ones, dictionaries of areas, are a few megabytes.
+const DefaultMaxBodySize = 32 << 20
+
+// ErrBodyTooLarge is returned for responses exceeding the maximum body size.
+var ErrBodyTooLarge = errors.New("response body is too large")
+
+// readBody reads the whole decoded body of the response and closes it.
+func (c *Client) readBody(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	limit := c.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	body, err := httpbody.Decode(resp.Header.Get("Content-Encoding"), resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding response of %s: %w", resp.Request.URL.Path, err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response of %s: %w", resp.Request.URL.Path, err)
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s returned more than %d bytes", ErrBodyTooLarge, resp.Request.URL.Path, limit)
+	}
+
+	return data, nil
+}
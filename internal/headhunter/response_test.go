@@ -0,0 +1,141 @@
+package headhunter
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"go.uber.org/zap"
+)
+
+func encode(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+
+	var (
+		b bytes.Buffer
+		w io.WriteCloser
+	)
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&b)
+	case "deflate":
+		w = zlib.NewWriter(&b)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&b, flate.DefaultCompression)
+	case "br":
+		w = brotli.NewWriter(&b)
+	default:
+		return data
+	}
+
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReadBody(t *testing.T) {
+	payload := []byte(`{"items": [{"id": "1", "name": "Go developer"}], "pages": 1}`)
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		limit    int64
+		err      string
+	}{
+		{name: "plain", body: payload},
+		{name: "identity", encoding: "identity", body: payload},
+		{name: "gzip", encoding: "gzip", body: encode(t, "gzip", payload)},
+		{name: "deflate", encoding: "deflate", body: encode(t, "deflate", payload)},
+		{name: "raw deflate", encoding: "deflate", body: encode(t, "raw-deflate", payload)},
+		{name: "brotli", encoding: "br", body: encode(t, "br", payload)},
+		{name: "gzip over brotli", encoding: "br, gzip", body: encode(t, "gzip", encode(t, "br", payload))},
+		{name: "unsupported", encoding: "zstd", body: payload, err: `unsupported content encoding "zstd"`},
+		{name: "broken gzip", encoding: "gzip", body: payload, err: "gzip: invalid header"},
+		{name: "too large", encoding: "br", body: encode(t, "br", payload), limit: 10, err: ErrBodyTooLarge.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := New(context.Background(), "", zap.NewNop())
+			client.MaxBodySize = tt.limit
+
+			body := &closeTracker{Reader: bytes.NewReader(tt.body)}
+			resp := &http.Response{
+				Header:  http.Header{"Content-Encoding": []string{tt.encoding}},
+				Body:    body,
+				Request: httptest.NewRequest(http.MethodGet, "/vacancies", nil),
+			}
+
+			data, err := client.readBody(resp)
+			if !body.closed {
+				t.Fatalf("body is not closed")
+			}
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(data, payload) {
+				t.Fatalf("unexpected body: %s", data)
+			}
+		})
+	}
+}
+
+func TestGetJSONDecodesBrotli(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "br") {
+			t.Errorf("brotli is not accepted: %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "br")
+		w.Write(encode(t, "br", []byte(`{"id": "42", "name": "Go developer"}`)))
+	}))
+	defer server.Close()
+
+	client := New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+
+	vacancy, err := client.GetVacancy("42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vacancy.Name != "Go developer" {
+		t.Fatalf("unexpected vacancy: %+v", vacancy)
+	}
+
+	client.MaxBodySize = 8
+	if _, err := client.GetVacancy("42"); !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("expected ErrBodyTooLarge, got %v", err)
+	}
+}
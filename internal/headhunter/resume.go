@@ -1,18 +1,68 @@
 package headhunter
 
 import (
+	"errors"
 	"fmt"
-
-	"github.com/mitchellh/mapstructure"
+	"net/http"
+	"time"
 )
 
+// timeLayout is the format of dates in hh.ru API responses, e.g. 2024-01-02T15:04:05+0300.
+const timeLayout = "2006-01-02T15:04:05-0700"
+
+// FormatTime formats the time the way hh.ru expects it in search parameters like date_from.
+func FormatTime(t time.Time) string {
+	return t.Format(timeLayout)
+}
+
 type Resumes struct {
 	Items []*Resume
 }
 
+const (
+	ResumeStatusPublished = "published"
+	// PublishCooldown is the minimal interval between raising a resume in search.
+	PublishCooldown = 4 * time.Hour
+)
+
+// ErrPublishCooldown is returned when the resume was raised in search less than PublishCooldown ago.
+var ErrPublishCooldown = errors.New("resume can't be published yet")
+
 type Resume struct {
 	Title string
 	ID    string `json:"id,omitempty"`
+	// The fields below are filled by the list of mine resumes only.
+	Status             *ResumeStatus `json:"status,omitempty"`
+	AlternateURL       string        `json:"alternate_url,omitempty"`
+	UpdatedAt          string        `json:"updated_at,omitempty"`
+	CanPublishOrUpdate bool          `json:"can_publish_or_update,omitempty"`
+	NextPublishAt      string        `json:"next_publish_at,omitempty"`
+	TotalViews         int           `json:"total_views,omitempty"`
+	NewViews           int           `json:"new_views,omitempty"`
+}
+
+type ResumeStatus struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// IsPublished reports whether the resume is visible to employers.
+func (r *Resume) IsPublished() bool {
+	return r.Status != nil && r.Status.ID == ResumeStatusPublished
+}
+
+// NextPublish returns the time the resume can be raised in search again. Zero time means now.
+func (r *Resume) NextPublish() time.Time {
+	if r.CanPublishOrUpdate || r.NextPublishAt == "" {
+		return time.Time{}
+	}
+
+	next, err := time.Parse(timeLayout, r.NextPublishAt)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return next
 }
 
 type ResumeDetails struct {
@@ -24,16 +74,11 @@ type ResumeDetails struct {
 func (c *Client) getResumes(id string) (*Resumes, error) {
 	apiURLMineResumes := fmt.Sprintf("%s/resumes/%s", c.APIURL, id)
 
-	items, err := c.GetItems(apiURLMineResumes, nil, 0)
+	resumes, err := collect(items[*Resume](c.ctx, c, apiURLMineResumes, nil))
 	if err != nil {
 		return nil, err
 	}
 
-	var resumes []*Resume
-	if err = mapstructure.Decode(items, &resumes); err != nil {
-		return nil, err
-	}
-
 	return &Resumes{
 		Items: resumes,
 	}, nil
@@ -53,6 +98,17 @@ func (r *Resumes) Titles() []string {
 	return ids
 }
 
+// Find returns the resume with the given id or title.
+func (r *Resumes) Find(idOrTitle string) *Resume {
+	for _, resume := range r.Items {
+		if resume.ID == idOrTitle {
+			return resume
+		}
+	}
+
+	return r.FindByTitle(idOrTitle)
+}
+
 func (r *Resumes) FindByTitle(title string) *Resume {
 	for _, resume := range r.Items {
 		if resume.Title == title {
@@ -77,3 +133,31 @@ func (c *Client) GetResumeRaw(id string) (map[string]any, error) {
 
 	return raw, nil
 }
+
+// PublishResume raises the resume in search results. hh.ru allows it once per PublishCooldown.
+func (c *Client) PublishResume(id string) error {
+	apiURL := fmt.Sprintf("%s/resumes/%s/publish", c.APIURL, id)
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, apiURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.request(c.setHeaders(req))
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.readBody(resp); err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", ErrPublishCooldown, resp.Status)
+	default:
+		return &StatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+}
@@ -0,0 +1,107 @@
+package headhunter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestResumeText(t *testing.T) {
+	var raw map[string]any
+	err := json.Unmarshal([]byte(`{
+		"title": "DevOps engineer",
+		"first_name": "Ivan",
+		"last_name": "Petrov",
+		"area": {"name": "Moscow"},
+		"salary": {"amount": 250000, "currency": "RUR"},
+		"total_experience": {"months": 62},
+		"skill_set": ["Kubernetes", "Go", " "],
+		"experience": [{"start": "2020-01-01", "end": null, "company": "Acme", "position": "SRE", "description": "Kept things\nrunning"}],
+		"education": {"level": {"name": "Higher"}, "primary": [{"name": "MSU", "result": "Physics", "year": 2015}]},
+		"language": [{"name": "English", "level": {"name": "B2"}}],
+		"skills": "<p>I like <b>automation</b></p>"
+	}`), &raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := ResumeText(raw)
+
+	for _, expected := range []string{
+		"DevOps engineer\nIvan Petrov\n",
+		"Salary: 250000 RUR\n",
+		"Experience: 5 years 2 months\n",
+		"Key skills:\nKubernetes, Go\n",
+		"- SRE — Acme (2020-01-01 – now)\n  Kept things\n  running\n",
+		"Education (Higher):\n- MSU, Physics 2015\n",
+		"- English: B2\n",
+		"About:\nI like automation\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected %q in:\n%s", expected, text)
+		}
+	}
+}
+
+func TestResumeNextPublish(t *testing.T) {
+	resume := &Resume{CanPublishOrUpdate: false, NextPublishAt: "2024-05-01T12:00:00+0300"}
+	if got := resume.NextPublish(); !got.Equal(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next publish time: %v", got)
+	}
+
+	resume.CanPublishOrUpdate = true
+	if !resume.NextPublish().IsZero() {
+		t.Fatal("resume that can be published must have zero next publish time")
+	}
+}
+
+func TestPublishResume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+
+		switch r.URL.Path {
+		case "/resumes/fresh/publish":
+			w.WriteHeader(http.StatusNoContent)
+		case "/resumes/touched/publish":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+
+	if err := client.PublishResume("fresh"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := client.PublishResume("touched"); !errors.Is(err, ErrPublishCooldown) {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+
+	if err := client.PublishResume("missing"); err == nil || errors.Is(err, ErrPublishCooldown) {
+		t.Fatalf("expected bad status error, got %v", err)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	if got := FormatTime(time.Date(2024, time.May, 6, 10, 30, 0, 0, moscow)); got != "2024-05-06T10:30:00+0300" {
+		t.Fatalf("unexpected time: %s", got)
+	}
+	if got := FormatTime(time.Date(2024, time.May, 6, 7, 30, 0, 0, time.UTC)); got != "2024-05-06T07:30:00+0000" {
+		t.Fatalf("unexpected time in UTC: %s", got)
+	}
+}
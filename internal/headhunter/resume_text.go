@@ -0,0 +1,147 @@
+package headhunter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResumeText renders the raw resume returned by GetResumeRaw as readable plain text.
+func ResumeText(raw map[string]any) string {
+	var b strings.Builder
+
+	name := strings.TrimSpace(strings.Join([]string{field(raw, "first_name"), field(raw, "last_name")}, " "))
+	fmt.Fprintf(&b, "%s\n", field(raw, "title"))
+	if name != "" {
+		fmt.Fprintf(&b, "%s\n", name)
+	}
+
+	line(&b, "Area", field(raw, "area", "name"))
+	line(&b, "Salary", strings.TrimSpace(field(raw, "salary", "amount")+" "+field(raw, "salary", "currency")))
+	line(&b, "Experience", experienceDuration(raw))
+	line(&b, "Employment", strings.Join(names(raw, "employments"), ", "))
+	line(&b, "Schedule", strings.Join(names(raw, "schedules"), ", "))
+	line(&b, "Status", field(raw, "status", "name"))
+	line(&b, "URL", field(raw, "alternate_url"))
+
+	if skills := strings.Join(stringList(raw["skill_set"]), ", "); skills != "" {
+		fmt.Fprintf(&b, "\nKey skills:\n%s\n", skills)
+	}
+
+	if jobs := maps(raw["experience"]); len(jobs) > 0 {
+		b.WriteString("\nWork experience:\n")
+		for _, job := range jobs {
+			end := field(job, "end")
+			if end == "" {
+				end = "now"
+			}
+			fmt.Fprintf(&b, "- %s — %s (%s – %s)\n", field(job, "position"), field(job, "company"), field(job, "start"), end)
+			if description := normalizeText(field(job, "description")); description != "" {
+				fmt.Fprintf(&b, "  %s\n", strings.ReplaceAll(description, "\n", "\n  "))
+			}
+		}
+	}
+
+	if education, ok := raw["education"].(map[string]any); ok {
+		items := maps(education["primary"])
+		if len(items) > 0 {
+			fmt.Fprintf(&b, "\nEducation (%s):\n", field(education, "level", "name"))
+			for _, item := range items {
+				fmt.Fprintf(&b, "- %s, %s %s\n", field(item, "name"), field(item, "result"), field(item, "year"))
+			}
+		}
+	}
+
+	if languages := maps(raw["language"]); len(languages) > 0 {
+		b.WriteString("\nLanguages:\n")
+		for _, language := range languages {
+			fmt.Fprintf(&b, "- %s: %s\n", field(language, "name"), field(language, "level", "name"))
+		}
+	}
+
+	if about := normalizeText(StripHTML(field(raw, "skills"))); about != "" {
+		fmt.Fprintf(&b, "\nAbout:\n%s\n", about)
+	}
+
+	return b.String()
+}
+
+func line(b *strings.Builder, label, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "%s: %s\n", label, value)
+	}
+}
+
+func experienceDuration(raw map[string]any) string {
+	months, ok := lookup(raw, "total_experience", "months").(float64)
+	if !ok || months <= 0 {
+		return ""
+	}
+
+	years, rest := int(months)/12, int(months)%12
+	switch {
+	case years == 0:
+		return fmt.Sprintf("%d months", rest)
+	case rest == 0:
+		return fmt.Sprintf("%d years", years)
+	default:
+		return fmt.Sprintf("%d years %d months", years, rest)
+	}
+}
+
+// field returns the nested value as a string. Missing values are empty.
+func field(m map[string]any, path ...string) string {
+	switch v := lookup(m, path...).(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return fmt.Sprintf("%g", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func lookup(m map[string]any, path ...string) any {
+	var current any = m
+	for _, key := range path {
+		next, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = next[key]
+	}
+	return current
+}
+
+func maps(v any) []map[string]any {
+	list, _ := v.([]any)
+	result := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func names(raw map[string]any, key string) []string {
+	var result []string
+	for _, item := range maps(raw[key]) {
+		if name := field(item, "name"); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func stringList(v any) []string {
+	list, _ := v.([]any)
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			result = append(result, strings.TrimSpace(s))
+		}
+	}
+	return result
+}
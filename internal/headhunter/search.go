@@ -1,12 +1,16 @@
 package headhunter
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 
-	"github.com/mitchellh/mapstructure"
+	"go.uber.org/zap"
 )
 
 const (
@@ -15,86 +19,214 @@ const (
 
 type SearchParams struct {
 	Text string `yaml:"text"`
+	// ExcludedText is words which must not be found in vacancies.
+	ExcludedText string `yaml:"excluded_text" mapstructure:"excluded_text"`
 	// hhparam is custom tag for reflect. Please see below.
-	Areas       []int    `hhparam:"area"`
-	Clusters    bool     `yaml:"clusters"`
-	OrderBy     string   `yaml:"order_by"`
-	Employer    uint     `yaml:"employer_id" mapstructure:"employer_id"`
-	SearchField string   `yaml:"search_field" mapstructure:"search_field"`
-	Schedules   []string `hhparam:"schedule"`
-	PerPage     string   `yaml:"per_page" mapstructure:"per_page"`
-	Experience  string   `yaml:"experience"`
-	Period      uint     `yaml:"period"`
-	Limit       int      `yaml:"limit" mapstructure:"limit" hhparam:"-"`
+	// Areas and other dictionary values may be names in the config. They are resolved to ids before the search.
+	Areas    []string `hhparam:"area"`
+	Clusters bool     `yaml:"clusters"`
+	OrderBy  string   `yaml:"order_by"`
+	Employer uint     `yaml:"employer_id" mapstructure:"employer_id"`
+	// SearchField limits the text search to the name, company_name or description. Several values are allowed.
+	SearchField       []string `yaml:"search_field" mapstructure:"search_field"`
+	Schedules         []string `hhparam:"schedule"`
+	Employments       []string `hhparam:"employment"`
+	ProfessionalRoles []string `hhparam:"professional_role" mapstructure:"professional_roles"`
+	Industries        []string `hhparam:"industry"`
+	Labels            []string `hhparam:"label"`
+	Metro             []string `hhparam:"metro"`
+	PerPage           string   `yaml:"per_page" mapstructure:"per_page"`
+	Experience        string   `yaml:"experience"`
+	// Salary is the expected salary in Currency. Vacancies without a salary are included unless OnlyWithSalary is set.
+	Salary         uint   `yaml:"salary"`
+	Currency       string `yaml:"currency"`
+	OnlyWithSalary bool   `yaml:"only_with_salary" mapstructure:"only_with_salary"`
+	// Period is the number of days to search in. It can't be used with DateFrom and DateTo.
+	Period   uint   `yaml:"period"`
+	DateFrom string `yaml:"date_from" mapstructure:"date_from"`
+	DateTo   string `yaml:"date_to" mapstructure:"date_to"`
+	// The coordinates limit the search to an area on the map. All four are required together.
+	TopLat    *float64 `yaml:"top_lat" mapstructure:"top_lat"`
+	BottomLat *float64 `yaml:"bottom_lat" mapstructure:"bottom_lat"`
+	LeftLng   *float64 `yaml:"left_lng" mapstructure:"left_lng"`
+	RightLng  *float64 `yaml:"right_lng" mapstructure:"right_lng"`
+	Limit     int      `yaml:"limit" mapstructure:"limit" hhparam:"-"`
 }
 
-func (c *Client) search(params *SearchParams) (*Vacancies, error) {
-	var vacancies []*Vacancy
+// SearchParamField returns the config key of the search parameter sent as the query parameter
+// and whether it takes a list of values.
+func SearchParamField(param string) (key string, list bool, ok bool) {
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(SearchParams{})) {
+		if queryKey(field) != param {
+			continue
+		}
+
+		key = field.Tag.Get("mapstructure")
+		if key == "" {
+			key = strings.ToLower(field.Name)
+		}
 
-	// Set per_page max as possible. It should be faster.
-	if params.PerPage == "" {
-		params.PerPage = perPage
+		return key, field.Type.Kind() == reflect.Slice, true
 	}
 
-	q := buildParams(params)
-	apiURLSearch := fmt.Sprintf("%s%s", c.APIURL, SearchPath)
+	return "", false, false
+}
 
-	items, err := c.GetItems(apiURLSearch, q, params.Limit)
-	if err != nil {
-		return nil, err
+// Validate checks the combinations of parameters rejected by hh.ru.
+func (p *SearchParams) Validate() error {
+	if p.Period > 0 && (p.DateFrom != "" || p.DateTo != "") {
+		return errors.New("period can't be used together with date_from or date_to")
 	}
 
-	cfg := &mapstructure.DecoderConfig{
-		Metadata: nil,
-		Result:   &vacancies,
-		TagName:  "json",
+	coordinates := 0
+	for _, c := range []*float64{p.TopLat, p.BottomLat, p.LeftLng, p.RightLng} {
+		if c != nil {
+			coordinates++
+		}
+	}
+	if coordinates != 0 && coordinates != 4 {
+		return errors.New("top_lat, bottom_lat, left_lng and right_lng must be set together")
+	}
+
+	return nil
+}
+
+func (c *Client) search(params *SearchParams) (*Vacancies, error) {
+	vacancies, err := collect(c.SearchVacancies(c.ctx, params))
+	if err != nil {
+		return nil, err
 	}
-	decoder, _ := mapstructure.NewDecoder(cfg)
-	decoder.Decode(items)
 
 	return &Vacancies{
 		Items: vacancies,
 	}, nil
 }
 
-func buildParams(params *SearchParams) url.Values {
-	q := url.Values{}
-	fields := reflect.VisibleFields(reflect.TypeOf(*params))
-	// TODO: need create test for this
-	for _, field := range fields {
-		// Our custom tag is using here.
-		key := field.Tag.Get("hhparam")
-		if key == "" {
-			// Failover to default tag if our tag do not exist.
-			key = field.Tag.Get("yaml")
+// SearchVacancies yields vacancies as pages are loaded, so a consumer may start before the last page
+// and stop the search early. Queries deeper than the hh.ru pagination limit are sliced and merged.
+// The first request or decoding error is yielded and stops the iteration.
+func (c *Client) SearchVacancies(ctx context.Context, params *SearchParams) iter.Seq2[*Vacancy, error] {
+	return func(yield func(*Vacancy, error) bool) {
+		if err := params.Validate(); err != nil {
+			yield(nil, fmt.Errorf("invalid search parameters: %w", err))
+			return
 		}
-		if key == "-" {
-			continue
+
+		search := *params
+		// Set per_page max as possible. It should be faster.
+		if search.PerPage == "" {
+			search.PerPage = perPage
 		}
-		kind := field.Type.Kind()
-		switch kind {
-		case reflect.Slice:
-
-			s := reflect.ValueOf(params).Elem().Field(field.Index[0]).Interface()
-			switch v := s.(type) {
-			case []int:
-				for _, value := range v {
-					q.Add(key, strconv.Itoa(value))
-				}
-
-			case []string:
-				for _, value := range v {
-					q.Add(key, value)
-				}
+
+		collected := newCollector(search.Limit)
+		// Slicing pays off only when the results beyond the depth are needed.
+		sliceable := search.Limit == 0 || search.Limit > maxSearchDepth
+
+		found := 0
+		for page, err := range pages[*Vacancy](ctx, c, c.APIURL+SearchPath, buildParams(&search)) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			// Decide by the first page: the slices fetch the same vacancies again otherwise.
+			if page.Page == 0 && sliceable && page.Found > maxSearchDepth {
+				found = page.Found
+				break
 			}
 
-		default:
-			value := fmt.Sprintf("%v", reflect.ValueOf(params).Elem().Field(field.Index[0]).Interface())
-			if value != "" && value != "0" {
-				q.Set(key, value)
+			if !collected.yieldNew(page.Items, yield) {
+				return
 			}
 		}
+
+		if found == 0 {
+			return
+		}
+
+		c.logger.Info("search exceeds the depth limit, slicing the query",
+			zap.Int("found", found),
+			zap.Int("depth", maxSearchDepth),
+		)
+
+		c.searchSliced(ctx, &search, found, collected, yield)
+	}
+}
+
+// buildParams converts the search parameters to the query. Zero values and false booleans are omitted.
+// Fields where zero is a valid value (coordinates) are pointers and sent whenever set.
+func buildParams(params *SearchParams) url.Values {
+	q := url.Values{}
+	value := reflect.ValueOf(params).Elem()
+
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		key := queryKey(field)
+		if key == "" || key == "-" {
+			continue
+		}
+
+		for _, v := range queryValues(value.FieldByIndex(field.Index)) {
+			q.Add(key, v)
+		}
 	}
 
 	return q
 }
+
+func queryKey(field reflect.StructField) string {
+	// Our custom tag is using here.
+	key := field.Tag.Get("hhparam")
+	if key == "" {
+		// Failover to default tag if our tag do not exist.
+		key = field.Tag.Get("yaml")
+	}
+
+	return key
+}
+
+func queryValues(v reflect.Value) []string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return queryValues(v.Elem())
+
+	case reflect.Slice:
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, queryValues(v.Index(i))...)
+		}
+		return values
+
+	case reflect.String:
+		if v.String() == "" {
+			return nil
+		}
+		return []string{v.String()}
+
+	case reflect.Bool:
+		if !v.Bool() {
+			return nil
+		}
+		return []string{"true"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() == 0 {
+			return nil
+		}
+		return []string{strconv.FormatInt(v.Int(), 10)}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() == 0 {
+			return nil
+		}
+		return []string{strconv.FormatUint(v.Uint(), 10)}
+
+	case reflect.Float32, reflect.Float64:
+		return []string{strconv.FormatFloat(v.Float(), 'f', -1, 64)}
+
+	default:
+		return []string{fmt.Sprint(v.Interface())}
+	}
+}
@@ -0,0 +1,260 @@
+package headhunter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestBuildParams(t *testing.T) {
+	zero, north, west := 0.0, 55.5, 37.25
+
+	tests := []struct {
+		name   string
+		params *SearchParams
+		expect url.Values
+	}{
+		{name: "empty", params: &SearchParams{}, expect: url.Values{}},
+		{
+			name: "scalars",
+			params: &SearchParams{
+				Text:           "golang",
+				ExcludedText:   "php",
+				OrderBy:        "publication_time",
+				Employer:       42,
+				PerPage:        "100",
+				Experience:     "between1And3",
+				Salary:         200000,
+				Currency:       "RUR",
+				OnlyWithSalary: true,
+				Period:         7,
+				Limit:          10,
+			},
+			expect: url.Values{
+				"text":             {"golang"},
+				"excluded_text":    {"php"},
+				"order_by":         {"publication_time"},
+				"employer_id":      {"42"},
+				"per_page":         {"100"},
+				"experience":       {"between1And3"},
+				"salary":           {"200000"},
+				"currency":         {"RUR"},
+				"only_with_salary": {"true"},
+				"period":           {"7"},
+			},
+		},
+		{
+			name: "lists",
+			params: &SearchParams{
+				Areas:             []string{"1", "113"},
+				SearchField:       []string{"name", "description"},
+				Schedules:         []string{"remote"},
+				Employments:       []string{"full", "part"},
+				ProfessionalRoles: []string{"96"},
+				Industries:        []string{"7.540"},
+				Labels:            []string{"with_address", "accept_handicapped"},
+				Metro:             []string{"1.2"},
+			},
+			expect: url.Values{
+				"area":              {"1", "113"},
+				"search_field":      {"name", "description"},
+				"schedule":          {"remote"},
+				"employment":        {"full", "part"},
+				"professional_role": {"96"},
+				"industry":          {"7.540"},
+				"label":             {"with_address", "accept_handicapped"},
+				"metro":             {"1.2"},
+			},
+		},
+		{
+			name: "dates and coordinates",
+			params: &SearchParams{
+				Clusters:  false,
+				DateFrom:  "2024-05-01",
+				DateTo:    "2024-05-31T23:59:59+0300",
+				TopLat:    &north,
+				BottomLat: &zero,
+				LeftLng:   &zero,
+				RightLng:  &west,
+			},
+			expect: url.Values{
+				"date_from":  {"2024-05-01"},
+				"date_to":    {"2024-05-31T23:59:59+0300"},
+				"top_lat":    {"55.5"},
+				"bottom_lat": {"0"},
+				"left_lng":   {"0"},
+				"right_lng":  {"37.25"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := buildParams(tt.params); !reflect.DeepEqual(got, tt.expect) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestSearchParamsValidate(t *testing.T) {
+	lat := 55.5
+
+	tests := []struct {
+		name   string
+		params *SearchParams
+		err    string
+	}{
+		{name: "empty", params: &SearchParams{}},
+		{name: "period", params: &SearchParams{Period: 3}},
+		{name: "dates", params: &SearchParams{DateFrom: "2024-05-01", DateTo: "2024-05-02"}},
+		{name: "period with dates", params: &SearchParams{Period: 3, DateFrom: "2024-05-01"}, err: "period"},
+		{name: "full box", params: &SearchParams{TopLat: &lat, BottomLat: &lat, LeftLng: &lat, RightLng: &lat}},
+		{name: "partial box", params: &SearchParams{TopLat: &lat, BottomLat: &lat}, err: "must be set together"},
+	}
+
+	for _, tt := range tests {
+		err := tt.params.Validate()
+		if tt.err == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatalf("%s: expected error %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestSearchClusters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("clusters") != "true" {
+			t.Errorf("clusters are not requested: %s", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `{"items": [], "found": 120, "pages": 120, "page": 0, "per_page": 1, "clusters": [
+			{"id": "area", "name": "Регион", "items": [
+				{"name": "Москва", "url": "https://api.hh.ru/vacancies?text=go&area=113&area=1&clusters=true", "count": 80}
+			]},
+			{"id": "salary", "name": "Уровень зарплаты", "items": [
+				{"name": "от 200000 руб.", "url": "https://api.hh.ru/vacancies?text=go&area=113&salary=200000&only_with_salary=true", "count": 30}
+			]}
+		]}`)
+	}))
+	defer server.Close()
+
+	client := New(context.Background(), "", zap.NewNop())
+	client.APIURL = server.URL
+
+	params := &SearchParams{Text: "go", Areas: []string{"113"}}
+	result, err := client.SearchClusters(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Found != 120 || len(result.Clusters) != 2 || result.Clusters[0].Items[0].Count != 80 {
+		t.Fatalf("unexpected clusters: %+v", result)
+	}
+
+	refinement, err := result.Clusters[0].Items[0].Refinement(params)
+	if err != nil || !reflect.DeepEqual(refinement, url.Values{"area": {"1"}}) {
+		t.Fatalf("unexpected refinement: %v %v", refinement, err)
+	}
+
+	refinement, err = result.Clusters[1].Items[0].Refinement(params)
+	if err != nil || !reflect.DeepEqual(refinement, url.Values{"salary": {"200000"}, "only_with_salary": {"true"}}) {
+		t.Fatalf("unexpected refinement: %v %v", refinement, err)
+	}
+
+	if _, err := (&ClusterItem{URL: "https://api.hh.ru/vacancies?text=go&area=113"}).Refinement(params); err == nil {
+		t.Fatalf("expected error for the item without changes")
+	}
+}
+
+func TestSearchParamField(t *testing.T) {
+	tests := []struct {
+		param string
+		key   string
+		list  bool
+		ok    bool
+	}{
+		{param: "area", key: "areas", list: true, ok: true},
+		{param: "professional_role", key: "professional_roles", list: true, ok: true},
+		{param: "salary", key: "salary", ok: true},
+		{param: "only_with_salary", key: "only_with_salary", ok: true},
+		{param: "employer_id", key: "employer_id", ok: true},
+		{param: "unknown"},
+	}
+
+	for _, tt := range tests {
+		key, list, ok := SearchParamField(tt.param)
+		if key != tt.key || list != tt.list || ok != tt.ok {
+			t.Fatalf("%s: expected %s %v %v, got %s %v %v", tt.param, tt.key, tt.list, tt.ok, key, list, ok)
+		}
+	}
+}
+
+func TestSearchVacancies(t *testing.T) {
+	requested := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		page := r.URL.Query().Get("page")
+		if page == "2" {
+			fmt.Fprint(w, `{"items": [{"id": 5}], "found": 6, "pages": 3, "page": 2}`)
+			return
+		}
+		if page == "" {
+			page = "0"
+		}
+		fmt.Fprintf(w, `{"items": [{"id": "%[1]s-a"}, {"id": "%[1]s-b"}], "found": 6, "pages": 3, "page": %[1]s}`, page)
+	}))
+	defer server.Close()
+
+	client := New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+
+	t.Run("consumer stops early", func(t *testing.T) {
+		requested = 0
+		var ids []string
+		for vacancy, err := range client.SearchVacancies(context.Background(), &SearchParams{Text: "go"}) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids = append(ids, vacancy.ID)
+			if len(ids) == 3 {
+				break
+			}
+		}
+
+		if !reflect.DeepEqual(ids, []string{"0-a", "0-b", "1-a"}) || requested != 2 {
+			t.Fatalf("unexpected vacancies %v after %d requests", ids, requested)
+		}
+	})
+
+	t.Run("decoding error surfaces", func(t *testing.T) {
+		_, err := client.Search(&SearchParams{Text: "go"})
+		if err == nil || !strings.Contains(err.Error(), "decoding response") {
+			t.Fatalf("expected decoding error, got %v", err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var err error
+		for _, err = range client.SearchVacancies(ctx, &SearchParams{Text: "go"}) {
+		}
+
+		if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+			t.Fatalf("expected cancellation error, got %v", err)
+		}
+	})
+}
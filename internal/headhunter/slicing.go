@@ -0,0 +1,209 @@
+package headhunter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxSearchDepth is the number of results hh.ru returns for a single query regardless of found.
+	maxSearchDepth = 2000
+	// defaultSearchWindow is the search period when neither period nor dates are set.
+	defaultSearchWindow = 30 * 24 * time.Hour
+	// minSliceWindow stops splitting date ranges. Results of a deeper slice are lost.
+	minSliceWindow = time.Hour
+)
+
+// window is a publication date range. Open means no lower bound: the oldest slice keeps
+// vacancies published before the default search window.
+type window struct {
+	from, to time.Time
+	open     bool
+}
+
+// collector passes vacancies of several queries to the consumer without duplicates.
+type collector struct {
+	seen  map[string]bool
+	count int
+	limit int
+}
+
+func newCollector(limit int) *collector {
+	return &collector{seen: make(map[string]bool), limit: limit}
+}
+
+// yieldNew passes vacancies not seen before to the consumer. It reports whether the search
+// should go on: the consumer did not stop and the limit is not reached.
+func (c *collector) yieldNew(vacancies []*Vacancy, yield func(*Vacancy, error) bool) bool {
+	for _, vacancy := range vacancies {
+		if c.full() {
+			return false
+		}
+
+		if c.seen[vacancy.ID] {
+			continue
+		}
+		c.seen[vacancy.ID] = true
+		c.count++
+
+		if !yield(vacancy, nil) {
+			return false
+		}
+	}
+
+	return !c.full()
+}
+
+func (c *collector) full() bool {
+	return c.limit > 0 && c.count >= c.limit
+}
+
+// searchSliced splits the query by areas and publication dates so every slice fits into
+// the search depth. Salary is not sliced: bands would drop vacancies without a salary.
+// It reports whether the search should go on like collector.yieldNew.
+func (c *Client) searchSliced(ctx context.Context, params *SearchParams, found int, collected *collector, yield func(*Vacancy, error) bool) bool {
+	w, err := searchWindow(params)
+	if err != nil {
+		yield(nil, err)
+		return false
+	}
+
+	areas := [][]string{params.Areas}
+	if len(params.Areas) > 1 {
+		areas = areas[:0]
+		for _, area := range params.Areas {
+			areas = append(areas, []string{area})
+		}
+	}
+
+	for _, area := range areas {
+		slice := *params
+		slice.Areas = area
+		slice.Period = 0
+
+		if !c.searchWindow(ctx, &slice, w, collected, yield) {
+			break
+		}
+	}
+
+	c.logger.Info("search coverage",
+		zap.Int("found", found),
+		zap.Int("fetched", collected.count),
+		zap.String("coverage", fmt.Sprintf("%.1f%%", 100*float64(collected.count)/float64(found))),
+	)
+
+	return !collected.full()
+}
+
+// searchWindow fetches the vacancies published within the window. Windows with too many
+// results are split in halves, the newer half goes first.
+func (c *Client) searchWindow(ctx context.Context, params *SearchParams, w window, collected *collector, yield func(*Vacancy, error) bool) bool {
+	slice := *params
+	slice.DateTo = w.to.Format(timeLayout)
+	slice.DateFrom = ""
+	if !w.open {
+		slice.DateFrom = w.from.Format(timeLayout)
+	}
+
+	q := buildParams(&slice)
+	apiURL := c.APIURL + SearchPath
+
+	found, err := c.countItems(ctx, apiURL, q)
+	if err != nil {
+		yield(nil, err)
+		return false
+	}
+
+	if found > maxSearchDepth && w.to.Sub(w.from) > minSliceWindow {
+		mid := w.from.Add(w.to.Sub(w.from) / 2).Truncate(time.Second)
+		return c.searchWindow(ctx, params, window{from: mid, to: w.to}, collected, yield) &&
+			c.searchWindow(ctx, params, window{from: w.from, to: mid, open: w.open}, collected, yield)
+	}
+
+	if found == 0 {
+		return true
+	}
+
+	fetched := 0
+	for page, err := range pages[*Vacancy](ctx, c, apiURL, q) {
+		if err != nil {
+			yield(nil, err)
+			return false
+		}
+
+		fetched += len(page.Items)
+		if !collected.yieldNew(page.Items, yield) {
+			return false
+		}
+	}
+
+	if found > fetched {
+		c.logger.Warn("search slice still exceeds the depth limit, some vacancies are lost",
+			zap.String("date_from", slice.DateFrom),
+			zap.String("date_to", slice.DateTo),
+			zap.Strings("areas", slice.Areas),
+			zap.Int("found", found),
+			zap.Int("fetched", fetched),
+		)
+	}
+
+	return true
+}
+
+// countItems returns the number of vacancies found by the query without fetching them.
+func (c *Client) countItems(ctx context.Context, apiURL string, q url.Values) (int, error) {
+	count := make(url.Values, len(q)+1)
+	for key, values := range q {
+		count[key] = values
+	}
+	count.Set("per_page", "1")
+
+	var response Page[struct{}]
+	if err := c.fetchJSON(ctx, apiURL, count, &response); err != nil {
+		return 0, err
+	}
+
+	return response.Found, nil
+}
+
+// searchWindow returns the publication date range of the search parameters.
+func searchWindow(params *SearchParams) (window, error) {
+	now := time.Now()
+	w := window{from: now.Add(-defaultSearchWindow), to: now, open: true}
+
+	if params.Period > 0 {
+		w.from, w.open = now.AddDate(0, 0, -int(params.Period)), false
+	}
+
+	if params.DateFrom != "" {
+		from, err := parseSearchDate(params.DateFrom)
+		if err != nil {
+			return w, fmt.Errorf("parsing date_from: %w", err)
+		}
+		w.from, w.open = from, false
+	}
+
+	if params.DateTo != "" {
+		to, err := parseSearchDate(params.DateTo)
+		if err != nil {
+			return w, fmt.Errorf("parsing date_to: %w", err)
+		}
+		w.to = to
+	}
+
+	return w, nil
+}
+
+func parseSearchDate(value string) (time.Time, error) {
+	for _, layout := range []string{timeLayout, time.RFC3339, "2006-01-02T15:04:05", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported date %q, use YYYY-MM-DD or ISO 8601", value)
+}
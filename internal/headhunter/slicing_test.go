@@ -0,0 +1,131 @@
+package headhunter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// depthLimitedServer serves vacancies published every 5 minutes over the last 20 days
+// and returns no more than maxSearchDepth of them for a query like hh.ru does. It counts
+// pages requested without dates, i.e. of the unsliced query.
+func depthLimitedServer(t *testing.T) (*httptest.Server, int, *atomic.Int32) {
+	unsliced := &atomic.Int32{}
+	now := time.Now()
+	var published []time.Time
+	for at := now.Add(-20 * 24 * time.Hour); at.Before(now); at = at.Add(5 * time.Minute) {
+		published = append(published, at)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("date_to") == "" {
+			unsliced.Add(1)
+		}
+
+		from, to := time.Time{}, now.Add(time.Hour)
+		if v := q.Get("date_from"); v != "" {
+			from, _ = time.Parse(timeLayout, v)
+		}
+		if v := q.Get("date_to"); v != "" {
+			to, _ = time.Parse(timeLayout, v)
+		}
+
+		var matched []map[string]any
+		for idx, at := range published {
+			if at.Truncate(time.Second).Before(from) || at.Truncate(time.Second).After(to) {
+				continue
+			}
+			matched = append(matched, map[string]any{"id": strconv.Itoa(idx)})
+		}
+
+		perPage, _ := strconv.Atoi(q.Get("per_page"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		reachable := min(len(matched), maxSearchDepth)
+		pages := (reachable + perPage - 1) / perPage
+
+		start, end := min(page*perPage, reachable), min((page+1)*perPage, reachable)
+		json.NewEncoder(w).Encode(map[string]any{
+			"items":    matched[start:end],
+			"found":    len(matched),
+			"pages":    pages,
+			"page":     page,
+			"per_page": perPage,
+		})
+	}))
+
+	return server, len(published), unsliced
+}
+
+func TestSearchSlicesDeepQueries(t *testing.T) {
+	server, total, unsliced := depthLimitedServer(t)
+	defer server.Close()
+
+	client := New(context.Background(), "token", zap.NewNop())
+	client.APIURL = server.URL
+
+	vacancies, err := client.Search(&SearchParams{Text: "go", PerPage: "100"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if vacancies.Len() != total {
+		t.Fatalf("expected %d vacancies, got %d", total, vacancies.Len())
+	}
+
+	seen := make(map[string]bool, vacancies.Len())
+	for _, vacancy := range vacancies.Items {
+		if seen[vacancy.ID] {
+			t.Fatalf("duplicate vacancy %s", vacancy.ID)
+		}
+		seen[vacancy.ID] = true
+	}
+
+	// Only the first page of the unsliced query is requested to decide on slicing.
+	if unsliced.Load() != 1 {
+		t.Fatalf("expected a single unsliced request, got %d", unsliced.Load())
+	}
+
+	limited, err := client.Search(&SearchParams{Text: "go", PerPage: "100", Limit: 2500})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limited.Len() != 2500 {
+		t.Fatalf("expected 2500 vacancies, got %d", limited.Len())
+	}
+
+	// A limit within the depth is served by the unsliced query.
+	unsliced.Store(0)
+	shallow, err := client.Search(&SearchParams{Text: "go", PerPage: "100", Limit: 150})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shallow.Len() != 150 || unsliced.Load() != 2 {
+		t.Fatalf("expected 150 vacancies in 2 unsliced requests, got %d in %d", shallow.Len(), unsliced.Load())
+	}
+}
+
+func TestSearchWindow(t *testing.T) {
+	w, err := searchWindow(&SearchParams{DateFrom: "2024-05-01", DateTo: "2024-05-03T12:00:00+0300"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.open || w.to.Sub(w.from) < 2*24*time.Hour {
+		t.Fatalf("unexpected window: %+v", w)
+	}
+
+	if w, _ := searchWindow(&SearchParams{}); !w.open {
+		t.Fatalf("window without dates must be open")
+	}
+
+	if _, err := searchWindow(&SearchParams{DateFrom: "yesterday"}); err == nil {
+		t.Fatalf("expected error for the bad date")
+	}
+}
@@ -6,7 +6,11 @@ import (
 	"math"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/net/html"
 )
 
 const (
@@ -14,6 +18,7 @@ const (
 	VacancyEmployerIDField = "EmployerID"
 
 	ExcludeReasonManualApply = "manual_apply"
+	ExcludeReasonTelegram    = "telegram"
 	excludeReasonAIFallback  = "ai_rejected"
 )
 
@@ -113,6 +118,9 @@ type AIAssessment struct {
 	Message string  `json:"message,omitempty"`
 	Raw     string  `json:"raw,omitempty"`
 	Error   string  `json:"error,omitempty"`
+	// ResumeID and ResumeTitle point to the best matching resume the application should be sent with.
+	ResumeID    string `json:"resume_id,omitempty"`
+	ResumeTitle string `json:"resume_title,omitempty"`
 }
 
 type ExcludedVacancies struct {
@@ -167,7 +175,40 @@ func (v *Vacancies) ToExcluded(actor ExcludeActor, reason string) *ExcludedVacan
 	return excluded
 }
 
+// excludeFileMu serializes reads and writes of exclude files shared by filters, prompts and the dashboard.
+var excludeFileMu sync.Mutex
+
 func GetExludedVacanciesFromFile(path string) (*ExcludedVacancies, error) {
+	excludeFileMu.Lock()
+	defer excludeFileMu.Unlock()
+
+	return readExcludedFile(path)
+}
+
+// UpdateExcludedFile reads the exclude file, applies the change and writes it back. No other read or
+// write of exclude files runs meanwhile, so concurrent changes are not lost. The file is not written
+// when the change fails.
+func UpdateExcludedFile(path string, change func(*ExcludedVacancies) error) (*ExcludedVacancies, error) {
+	excludeFileMu.Lock()
+	defer excludeFileMu.Unlock()
+
+	excluded, err := readExcludedFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(excluded); err != nil {
+		return nil, err
+	}
+
+	if err := excluded.writeFile(path); err != nil {
+		return nil, err
+	}
+
+	return excluded, nil
+}
+
+func readExcludedFile(path string) (*ExcludedVacancies, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -194,6 +235,27 @@ func (v *ExcludedVacancies) Append(s *ExcludedVacancies) {
 	v.Items = append(v.Items, s.Items...)
 }
 
+// Remove drops the vacancy with the given id from the list. It reports whether the vacancy was found.
+func (v *ExcludedVacancies) Remove(id string) bool {
+	for idx, vacancy := range v.Items {
+		if vacancy.ID == id {
+			v.Items = append(v.Items[:idx], v.Items[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the excluded vacancy with the given id or nil.
+func (v *ExcludedVacancies) Find(id string) *ExcludedVacancy {
+	for _, vacancy := range v.Items {
+		if vacancy.ID == id {
+			return vacancy
+		}
+	}
+	return nil
+}
+
 func (v *ExcludedVacancies) VacanciesIDs() []string {
 	ids := make([]string, 0)
 	for _, vacancy := range v.Items {
@@ -203,7 +265,14 @@ func (v *ExcludedVacancies) VacanciesIDs() []string {
 }
 
 func (v *ExcludedVacancies) ToFile(path string) error {
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	excludeFileMu.Lock()
+	defer excludeFileMu.Unlock()
+
+	return v.writeFile(path)
+}
+
+func (v *ExcludedVacancies) writeFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
 	if err != nil {
 		return err
 	}
@@ -217,6 +286,77 @@ func (v *ExcludedVacancies) ToFile(path string) error {
 	return nil
 }
 
+// SalaryString returns a human readable salary range or an empty string if salary is not specified.
+func (va *Vacancy) SalaryString() string {
+	s := va.Salary
+	switch {
+	case s.From > 0 && s.To > 0:
+		return strings.TrimSpace(fmt.Sprintf("%d-%d %s", s.From, s.To, s.Currency))
+	case s.From > 0:
+		return strings.TrimSpace(fmt.Sprintf("from %d %s", s.From, s.Currency))
+	case s.To > 0:
+		return strings.TrimSpace(fmt.Sprintf("up to %d %s", s.To, s.Currency))
+	default:
+		return ""
+	}
+}
+
+// PlainDescription returns the vacancy description with HTML markup stripped.
+func (va *Vacancy) PlainDescription() string {
+	return StripHTML(va.Description)
+}
+
+// KeySkillNames returns names of the vacancy key skills.
+func (va *Vacancy) KeySkillNames() []string {
+	names := make([]string, 0, len(va.KeySkills))
+	for _, skill := range va.KeySkills {
+		names = append(names, skill.Name)
+	}
+	return names
+}
+
+// StripHTML converts HTML markup to plain text. Block elements and list items
+// are placed on separate lines, entities are unescaped and whitespace is collapsed.
+func StripHTML(s string) string {
+	var builder strings.Builder
+
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	for {
+		token := tokenizer.Next()
+		switch token {
+		case html.ErrorToken:
+			return normalizeText(builder.String())
+		case html.TextToken:
+			builder.WriteString(strings.ReplaceAll(string(tokenizer.Text()), "\n", " "))
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "li":
+				if token == html.StartTagToken {
+					builder.WriteString("\n- ")
+				}
+			case "br", "p", "div", "ul", "ol", "tr", "h1", "h2", "h3", "h4", "h5", "h6":
+				builder.WriteString("\n")
+			}
+		}
+	}
+}
+
+// normalizeText collapses spaces inside lines and keeps at most one empty line in a row.
+func normalizeText(s string) string {
+	lines := strings.Split(s, "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(result) == 0 || result[len(result)-1] == "") {
+			continue
+		}
+		result = append(result, line)
+	}
+
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
+
 func (va *Vacancy) GetStringField(name string) string {
 	switch name {
 	case VacancyIDField:
@@ -229,7 +369,6 @@ func (va *Vacancy) GetStringField(name string) string {
 	}
 }
 
-// TODO: need create test for this
 // Report by employer.
 func (v *Vacancies) ReportByEmployer() map[string][]map[string]string {
 	report := make(map[string][]map[string]string)
@@ -283,34 +422,51 @@ func (v *Vacancies) FindByID(id string) *Vacancy {
 	return nil
 }
 
-func (v *Vacancies) ExcludeWithTest() []string {
-	var excluded []string
-	for idx, vacancy := range v.Items {
-		if vacancy.HasTest {
-			v.RemoveByIndex(idx)
-			excluded = append(excluded, vacancy.ID)
-			break
-		}
-	}
-	return excluded
+// ExcludeWithTest removes vacancies requiring a test and returns them.
+func (v *Vacancies) ExcludeWithTest() []*Vacancy {
+	return v.excludeFunc(func(vacancy *Vacancy) bool {
+		return vacancy.HasTest
+	})
 }
 
-// TODO: need create test for this
-// Exclude function exclude vacancies from list by id.
-func (v *Vacancies) Exclude(name string, targets []string) []string {
-	var excluded []string
+// Exclude removes vacancies with the field matching any of targets and returns them.
+func (v *Vacancies) Exclude(name string, targets []string) []*Vacancy {
+	set := make(map[string]bool, len(targets))
 	for _, target := range targets {
-		for idx, vacancy := range v.Items {
-			if vacancy.GetStringField(name) == target {
-				v.RemoveByIndex(idx)
-				excluded = append(excluded, vacancy.ID)
-				break
-			}
+		set[target] = true
+	}
+
+	return v.excludeFunc(func(vacancy *Vacancy) bool {
+		return set[vacancy.GetStringField(name)]
+	})
+}
+
+// excludeFunc removes vacancies matched by the function preserving the order of the rest.
+func (v *Vacancies) excludeFunc(match func(*Vacancy) bool) []*Vacancy {
+	var excluded []*Vacancy
+	kept := make([]*Vacancy, 0, len(v.Items))
+
+	for _, vacancy := range v.Items {
+		if match(vacancy) {
+			excluded = append(excluded, vacancy)
+			continue
 		}
+		kept = append(kept, vacancy)
 	}
+
+	v.Items = kept
 	return excluded
 }
 
+// IDs returns ids of the vacancies.
+func IDs(vacancies []*Vacancy) []string {
+	ids := make([]string, 0, len(vacancies))
+	for _, vacancy := range vacancies {
+		ids = append(ids, vacancy.ID)
+	}
+	return ids
+}
+
 // RemoveByIndex remove vacancy from list by index. Do not preserve order.
 func (v *Vacancies) RemoveByIndex(idx int) {
 	v.Items[idx] = v.Items[len(v.Items)-1]
@@ -1,6 +1,13 @@
 package headhunter
 
-import "testing"
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
 
 func TestReportByEmployerIncludesAIResults(t *testing.T) {
 	vacancies := &Vacancies{
@@ -110,3 +117,125 @@ func TestReportByEmployerIncludesAIError(t *testing.T) {
 		t.Fatalf("did not expect ai_fit for error case")
 	}
 }
+
+func TestStripHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name:   "plain text",
+			input:  "Just text",
+			expect: "Just text",
+		},
+		{
+			name:   "paragraphs and entities",
+			input:  "<p><strong>About</strong> us &amp; you</p><p>Second\n   line</p>",
+			expect: "About us & you\n\nSecond line",
+		},
+		{
+			name:   "list items",
+			input:  "<p>Requirements:</p><ul><li>Go</li><li>Kubernetes</li></ul>",
+			expect: "Requirements:\n\n- Go\n- Kubernetes",
+		},
+		{
+			name:   "line breaks",
+			input:  "one<br>two<br/>three",
+			expect: "one\ntwo\nthree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := StripHTML(tt.input); got != tt.expect {
+				t.Fatalf("expected %q, got %q", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestExclude(t *testing.T) {
+	newVacancies := func() *Vacancies {
+		items := make([]*Vacancy, 0, 5)
+		for idx, employer := range []string{"a", "b", "a", "c", "a"} {
+			vacancy := &Vacancy{ID: string(rune('1' + idx)), HasTest: employer == "b" || employer == "c"}
+			vacancy.Employer.ID = employer
+			items = append(items, vacancy)
+		}
+		return &Vacancies{Items: items}
+	}
+
+	ids := func(v *Vacancies) string {
+		out := ""
+		for _, vacancy := range v.Items {
+			out += vacancy.ID
+		}
+		return out
+	}
+
+	v := newVacancies()
+	excluded := v.Exclude(VacancyEmployerIDField, []string{"a", "missing"})
+	if len(excluded) != 3 || ids(v) != "24" {
+		t.Fatalf("expected every vacancy of the employer to be excluded in order, got %v left %s", IDs(excluded), ids(v))
+	}
+
+	v = newVacancies()
+	excluded = v.Exclude(VacancyIDField, []string{"5", "1"})
+	if got := IDs(excluded); len(got) != 2 || got[0] != "1" || got[1] != "5" || ids(v) != "234" {
+		t.Fatalf("unexpected exclusion by id: %v left %s", got, ids(v))
+	}
+
+	v = newVacancies()
+	excluded = v.ExcludeWithTest()
+	if len(excluded) != 2 || ids(v) != "135" {
+		t.Fatalf("expected all vacancies with test to be excluded, got %v left %s", IDs(excluded), ids(v))
+	}
+}
+
+func TestUpdateExcludedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exclude.json")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent changes must not overwrite each other.
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := UpdateExcludedFile(path, func(excluded *ExcludedVacancies) error {
+				excluded.Append(&ExcludedVacancies{Items: []*ExcludedVacancy{{ID: strconv.Itoa(i)}}})
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	excluded, err := GetExludedVacanciesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(excluded.Items) != 20 {
+		t.Fatalf("expected 20 excluded vacancies, got %d", len(excluded.Items))
+	}
+
+	failed := errors.New("failed")
+	if _, err := UpdateExcludedFile(path, func(excluded *ExcludedVacancies) error {
+		excluded.Items = nil
+		return failed
+	}); !errors.Is(err, failed) {
+		t.Fatalf("expected the change error, got %v", err)
+	}
+
+	if excluded, _ = GetExludedVacanciesFromFile(path); len(excluded.Items) != 20 {
+		t.Fatalf("expected the file untouched by the failed change, got %d items", len(excluded.Items))
+	}
+}
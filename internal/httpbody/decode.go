@@ -0,0 +1,82 @@
+// Package httpbody decodes HTTP bodies compressed with the Content-Encoding.
+package httpbody
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Decode wraps the body into decoders of the Content-Encoding: gzip, deflate, br or their sequence.
+// Closing the result closes the decoders but not the body.
+func Decode(encoding string, body io.Reader) (io.ReadCloser, error) {
+	var (
+		reader  = body
+		closers []io.Closer
+	)
+
+	codings := strings.Split(encoding, ",")
+	// Encodings are listed in the order they were applied.
+	for i := len(codings) - 1; i >= 0; i-- {
+		switch coding := strings.ToLower(strings.TrimSpace(codings[i])); coding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(reader)
+			if err != nil {
+				return nil, err
+			}
+			reader = gz
+			closers = append(closers, gz)
+		case "deflate":
+			deflate, err := newDeflateReader(reader)
+			if err != nil {
+				return nil, err
+			}
+			reader = deflate
+			closers = append(closers, deflate)
+		case "br":
+			reader = brotli.NewReader(reader)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", coding)
+		}
+	}
+
+	return &decodedBody{Reader: reader, closers: closers}, nil
+}
+
+// newDeflateReader reads zlib streams as the standard requires and raw deflate some servers send instead.
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+
+	header, err := buffered.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	var errs []error
+	for _, closer := range b.closers {
+		errs = append(errs, closer.Close())
+	}
+
+	return errors.Join(errs...)
+}
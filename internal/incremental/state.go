@@ -0,0 +1,141 @@
+// Package incremental remembers the last successful search per profile so the next run
+// fetches only vacancies published since then.
+package incremental
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/utils"
+)
+
+const (
+	// DefaultFullScanEvery is the number of runs after which the full period is searched again.
+	DefaultFullScanEvery = 10
+	// Overlap is subtracted from the last search time since vacancies appear in search with a delay.
+	Overlap = 10 * time.Minute
+)
+
+// Profile is the search state of an account with a particular search.
+type Profile struct {
+	LastSearchAt time.Time `json:"last_search_at"`
+	// Runs counts incremental searches since the last full one.
+	Runs int `json:"runs"`
+}
+
+type State struct {
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// Plan is the way the next search goes.
+type Plan struct {
+	Key string
+	// Since is the publication time to search from. Zero means the full search.
+	Since time.Time
+}
+
+// Full reports whether the whole search window is scanned.
+func (p Plan) Full() bool {
+	return p.Since.IsZero()
+}
+
+// Key identifies the profile by the account and the search. Changing the search starts a new profile.
+// Dates and the period are ignored since incremental search changes them.
+func Key(account string, params *headhunter.SearchParams) string {
+	search := *params
+	search.DateFrom, search.DateTo, search.Period = "", "", 0
+
+	data, _ := json.Marshal(search)
+	sum := sha256.Sum256(data)
+
+	return account + "/" + hex.EncodeToString(sum[:8])
+}
+
+// Plan returns the next search of the profile. It is the full one for a new profile, after
+// fullScanEvery incremental runs or when the last search is older than the period.
+func (s *State) Plan(key string, fullScanEvery int, period time.Duration) Plan {
+	if fullScanEvery <= 0 {
+		fullScanEvery = DefaultFullScanEvery
+	}
+
+	profile, ok := s.Profiles[key]
+	switch {
+	case !ok, profile.LastSearchAt.IsZero():
+		return Plan{Key: key}
+	case profile.Runs+1 >= fullScanEvery:
+		return Plan{Key: key}
+	case period > 0 && time.Since(profile.LastSearchAt) > period:
+		return Plan{Key: key}
+	}
+
+	return Plan{Key: key, Since: profile.LastSearchAt.Add(-Overlap)}
+}
+
+// Done records the successful search started at the given time.
+func (s *State) Done(plan Plan, startedAt time.Time) {
+	if s.Profiles == nil {
+		s.Profiles = make(map[string]*Profile)
+	}
+
+	profile := &Profile{LastSearchAt: startedAt.UTC()}
+	if previous, ok := s.Profiles[plan.Key]; ok && !plan.Full() {
+		profile.Runs = previous.Runs + 1
+	}
+
+	s.Profiles[plan.Key] = profile
+}
+
+// Store keeps the state in a json file.
+type Store struct {
+	Path string
+}
+
+// Load reads the state. A missing file is an empty state.
+func (s *Store) Load() (*State, error) {
+	state := &State{Profiles: make(map[string]*Profile)}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decoding search state %s: %w", s.Path, err)
+	}
+
+	if state.Profiles == nil {
+		state.Profiles = make(map[string]*Profile)
+	}
+
+	return state, nil
+}
+
+func (s *Store) Save(state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(s.Path, data, 0o644)
+}
+
+// Update loads the state, applies the change and saves it. The state is shared by accounts and runs.
+func (s *Store) Update(change func(*State)) error {
+	state, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	change(state)
+
+	return s.Save(state)
+}
@@ -0,0 +1,65 @@
+package incremental
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+func TestKey(t *testing.T) {
+	base := Key("main", &headhunter.SearchParams{Text: "go", Period: 30})
+
+	if got := Key("main", &headhunter.SearchParams{Text: "go", DateFrom: "2024-05-01"}); got != base {
+		t.Fatalf("dates must not change the key: %s != %s", got, base)
+	}
+	if got := Key("main", &headhunter.SearchParams{Text: "rust"}); got == base {
+		t.Fatalf("search text must change the key")
+	}
+	if got := Key("second", &headhunter.SearchParams{Text: "go"}); got == base {
+		t.Fatalf("account must change the key")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "state.json")}
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := Key("main", &headhunter.SearchParams{Text: "go"})
+	period := 30 * 24 * time.Hour
+
+	var fulls []bool
+	for run := 0; run < 7; run++ {
+		plan := state.Plan(key, 3, period)
+		fulls = append(fulls, plan.Full())
+
+		if !plan.Full() && time.Since(plan.Since) < Overlap {
+			t.Fatalf("run %d: expected overlap, since %v", run, plan.Since)
+		}
+
+		state.Done(plan, time.Now())
+		if err := store.Save(state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if state, err = store.Load(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []bool{true, false, false, true, false, false, true}
+	for i := range expected {
+		if fulls[i] != expected[i] {
+			t.Fatalf("expected full scans %v, got %v", expected, fulls)
+		}
+	}
+
+	state.Profiles[key].LastSearchAt = time.Now().Add(-2 * period)
+	state.Profiles[key].Runs = 0
+	if !state.Plan(key, 3, period).Full() {
+		t.Fatalf("search older than the period must be full")
+	}
+}
@@ -8,6 +8,11 @@ import (
 )
 
 func New(json bool, debug bool) (*zap.Logger, error) {
+	return NewWithOutput(json, debug, "stdout")
+}
+
+// NewWithOutput creates a logger writing to the given zap output path (stdout, stderr or a file).
+func NewWithOutput(json bool, debug bool, output string) (*zap.Logger, error) {
 	level := zapcore.InfoLevel
 	encoding := "console"
 
@@ -22,7 +27,7 @@ func New(json bool, debug bool) (*zap.Logger, error) {
 	cfg := zap.Config{
 		Encoding:         encoding,
 		Level:            zap.NewAtomicLevelAt(level),
-		OutputPaths:      []string{"stdout"},
+		OutputPaths:      []string{output},
 		ErrorOutputPaths: []string{"stderr"},
 		EncoderConfig: zapcore.EncoderConfig{
 			MessageKey: "step",
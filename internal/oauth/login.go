@@ -0,0 +1,103 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Login runs the authorization code flow. It listens on the redirect URL, passes the authorization page
+// to open and waits for hh.ru to redirect the user back with the code.
+func (c *Client) Login(ctx context.Context, open func(authURL string)) (*Token, error) {
+	redirect, err := url.Parse(c.config.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redirect url: %w", err)
+	}
+
+	if redirect.Scheme != "http" {
+		return nil, fmt.Errorf("redirect url must be a local http address, got %s", c.config.RedirectURL)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("listen for redirect: %w", err)
+	}
+
+	results := make(chan callbackResult, 1)
+
+	path := redirect.Path
+	if path == "" {
+		path = "/"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		result := parseCallback(r.URL.Query(), state)
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "hh-responder is authorized. You can close this page.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	open(c.AuthCodeURL(state))
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return c.Exchange(ctx, result.code)
+	}
+}
+
+func parseCallback(q url.Values, state string) callbackResult {
+	if e := q.Get("error"); e != "" {
+		return callbackResult{err: fmt.Errorf("authorization failed: %s %s", e, q.Get("error_description"))}
+	}
+
+	if q.Get("state") != state {
+		return callbackResult{err: errors.New("authorization failed: state mismatch")}
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		return callbackResult{err: errors.New("authorization failed: code is missing")}
+	}
+
+	return callbackResult{code: code}
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,151 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAuthURL     = "https://hh.ru/oauth/authorize"
+	DefaultTokenURL    = "https://api.hh.ru/token"
+	DefaultRedirectURL = "http://127.0.0.1:8765/callback"
+
+	// expiryDelta refreshes tokens a bit earlier than they actually expire.
+	expiryDelta = time.Minute
+)
+
+// ErrNoRefreshToken is returned when the stored token can't be refreshed.
+var ErrNoRefreshToken = errors.New("refresh token is missing: run auth login")
+
+// Config describes the registered hh.ru application.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	RedirectURL  string
+}
+
+// Token is a pair of access and refresh tokens issued by hh.ru.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresIn    int       `json:"expires_in,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the access token is expired or about to expire.
+func (t *Token) Expired() bool {
+	if t.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().Add(expiryDelta).After(t.ExpiresAt)
+}
+
+type tokenError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// Client talks to the hh.ru OAuth endpoints.
+type Client struct {
+	config     Config
+	HTTPClient *http.Client
+}
+
+func New(config Config) *Client {
+	if config.AuthURL == "" {
+		config.AuthURL = DefaultAuthURL
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = DefaultTokenURL
+	}
+	if config.RedirectURL == "" {
+		config.RedirectURL = DefaultRedirectURL
+	}
+
+	return &Client{
+		config: config,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// AuthCodeURL returns the page where the user grants access to the application.
+func (c *Client) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", c.config.ClientID)
+	q.Set("state", state)
+	q.Set("redirect_uri", c.config.RedirectURL)
+
+	return c.config.AuthURL + "?" + q.Encode()
+}
+
+// Exchange trades the authorization code for tokens.
+func (c *Client) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.token(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {c.config.ClientID},
+		"client_secret": {c.config.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+	})
+}
+
+// Refresh issues a new pair of tokens. The old refresh token can't be used after that.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	if refreshToken == "" {
+		return nil, ErrNoRefreshToken
+	}
+
+	return c.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (c *Client) token(ctx context.Context, form url.Values) (*Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var tokenErr tokenError
+		if err := json.NewDecoder(resp.Body).Decode(&tokenErr); err == nil && tokenErr.Error != "" {
+			return nil, fmt.Errorf("bad status: %s: %s %s", resp.Status, tokenErr.Error, tokenErr.Description)
+		}
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	var token Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("decode token: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return nil, errors.New("token response has no access token")
+	}
+
+	if token.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().UTC().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return &token, nil
+}
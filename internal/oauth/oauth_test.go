@@ -0,0 +1,172 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type tokenServer struct {
+	*httptest.Server
+	forms []url.Values
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	t.Helper()
+
+	ts := &tokenServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		ts.forms = append(ts.forms, r.PostForm)
+
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != "the-code" || r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "code has already been used"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token": "access-1", "refresh_token": "refresh-1", "token_type": "bearer", "expires_in": 1209600}`))
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token": "access-2", "refresh_token": "refresh-2", "expires_in": 1209600}`))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func freeRedirectURL(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return "http://" + listener.Addr().String() + "/callback"
+}
+
+func TestLogin(t *testing.T) {
+	ts := newTokenServer(t)
+	client := New(Config{ClientID: "app", ClientSecret: "secret", TokenURL: ts.URL, RedirectURL: freeRedirectURL(t)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := client.Login(ctx, func(authURL string) {
+		parsed, err := url.Parse(authURL)
+		if err != nil {
+			t.Errorf("invalid auth url: %v", err)
+			return
+		}
+
+		q := parsed.Query()
+		if q.Get("client_id") != "app" || q.Get("response_type") != "code" {
+			t.Errorf("unexpected auth url: %s", authURL)
+		}
+
+		// The browser follows the redirect after the user allows access.
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "?code=the-code&state=" + q.Get("state"))
+			if err != nil {
+				t.Errorf("redirect: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+}
+
+func TestLoginStateMismatch(t *testing.T) {
+	client := New(Config{ClientID: "app", TokenURL: "http://127.0.0.1:1", RedirectURL: freeRedirectURL(t)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.Login(ctx, func(authURL string) {
+		parsed, _ := url.Parse(authURL)
+		go func() {
+			resp, err := http.Get(parsed.Query().Get("redirect_uri") + "?code=the-code&state=forged")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	})
+	if err == nil {
+		t.Fatal("expected state mismatch error")
+	}
+}
+
+func TestRefresher(t *testing.T) {
+	ts := newTokenServer(t)
+	store := &Store{Path: filepath.Join(t.TempDir(), "token.json")}
+
+	if err := store.Save(&Token{AccessToken: "access-1", RefreshToken: "refresh-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(store.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("token store must be private, got %v", info.Mode().Perm())
+	}
+
+	refresher := NewRefresher(New(Config{ClientID: "app", TokenURL: ts.URL}), store, zap.NewNop())
+
+	access, err := refresher.Refresh(context.Background(), "access-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if access != "access-2" {
+		t.Fatalf("expected refreshed token, got %s", access)
+	}
+
+	// A request rejected with the old token must not refresh again.
+	access, err = refresher.Refresh(context.Background(), "access-1")
+	if err != nil || access != "access-2" {
+		t.Fatalf("expected stored token, got %s %v", access, err)
+	}
+	if len(ts.forms) != 1 {
+		t.Fatalf("expected a single refresh, got %d", len(ts.forms))
+	}
+
+	data, err := os.ReadFile(store.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var saved Token
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.RefreshToken != "refresh-2" {
+		t.Fatalf("expected rotated refresh token in store, got %+v", saved)
+	}
+}
@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/utils"
+)
+
+// Store keeps tokens in a JSON file readable by the owner only.
+type Store struct {
+	Path string
+}
+
+// Load reads tokens from the store.
+func (s *Store) Load() (*Token, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading token store %q: %w", s.Path, err)
+	}
+
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("decoding token store %q: %w", s.Path, err)
+	}
+
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token store %q has no access token", s.Path)
+	}
+
+	return &token, nil
+}
+
+// Save replaces tokens in the store atomically.
+func (s *Store) Save(token *Token) error {
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := utils.WriteFileAtomic(s.Path, data, 0o600); err != nil {
+		return fmt.Errorf("writing token store %q: %w", s.Path, err)
+	}
+
+	return nil
+}
+
+// Refresher refreshes tokens kept in the store.
+type Refresher struct {
+	client *Client
+	store  *Store
+	logger *zap.Logger
+
+	mu sync.Mutex
+}
+
+func NewRefresher(client *Client, store *Store, logger *zap.Logger) *Refresher {
+	return &Refresher{
+		client: client,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Refresh issues new tokens, saves them and returns the new access token.
+// The stored token is re-read first: another process may have refreshed it already.
+func (r *Refresher) Refresh(ctx context.Context, rejected string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, err := r.store.Load()
+	if err != nil {
+		return "", err
+	}
+
+	if token.AccessToken != rejected && !token.Expired() {
+		r.logger.Debug("token was already refreshed", zap.String("store", r.store.Path))
+		return token.AccessToken, nil
+	}
+
+	refreshed, err := r.client.Refresh(ctx, token.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("refreshing token: %w", err)
+	}
+
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+
+	if err := r.store.Save(refreshed); err != nil {
+		return "", err
+	}
+
+	r.logger.Info("access token refreshed", zap.String("store", r.store.Path), zap.Time("expires_at", refreshed.ExpiresAt))
+
+	return refreshed.AccessToken, nil
+}
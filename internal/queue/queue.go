@@ -0,0 +1,150 @@
+// Package queue keeps applications waiting to be sent and submits them at a human-like pace:
+// with pauses, a random jitter, a limit per hour and only within working hours.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/utils"
+)
+
+// Item is an application waiting in the queue.
+type Item struct {
+	Account      string    `json:"account"`
+	VacancyID    string    `json:"vacancy_id"`
+	VacancyName  string    `json:"vacancy_name,omitempty"`
+	EmployerName string    `json:"employer_name,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	ResumeID     string    `json:"resume_id"`
+	ResumeTitle  string    `json:"resume_title,omitempty"`
+	Message      string    `json:"message"`
+	EnqueuedAt   time.Time `json:"enqueued_at"`
+	// Attempts counts failed submissions.
+	Attempts int `json:"attempts,omitempty"`
+	// NextAttemptAt postpones the retry after a temporary error.
+	NextAttemptAt time.Time `json:"next_attempt_at,omitzero"`
+	LastError     string    `json:"last_error,omitempty"`
+	// Failed items are not submitted anymore. They stay in the queue to be inspected and dropped.
+	Failed bool `json:"failed,omitempty"`
+}
+
+// NewItem makes the application to the vacancy with the resume.
+func NewItem(account string, vacancy *headhunter.Vacancy, resume *headhunter.Resume, message string) *Item {
+	return &Item{
+		Account:      account,
+		VacancyID:    vacancy.ID,
+		VacancyName:  vacancy.Name,
+		EmployerName: vacancy.Employer.Name,
+		URL:          vacancy.AlternateURL,
+		ResumeID:     resume.ID,
+		ResumeTitle:  resume.Title,
+		Message:      message,
+		EnqueuedAt:   time.Now().UTC(),
+	}
+}
+
+// Due reports whether the item may be submitted at the time.
+func (i *Item) Due(now time.Time) bool {
+	return !i.Failed && !now.Before(i.NextAttemptAt)
+}
+
+type State struct {
+	Items []*Item `json:"items"`
+	// Sent are the times of submissions within the last hour, shared by all accounts.
+	Sent []time.Time `json:"sent,omitempty"`
+}
+
+// Add appends the item unless the account has already queued the vacancy.
+func (s *State) Add(item *Item) bool {
+	if s.Find(item.Account, item.VacancyID) != nil {
+		return false
+	}
+
+	s.Items = append(s.Items, item)
+	return true
+}
+
+// Find returns the item of the account and the vacancy or nil.
+func (s *State) Find(account, vacancyID string) *Item {
+	for _, item := range s.Items {
+		if item.Account == account && item.VacancyID == vacancyID {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// Remove drops the items matching the condition and returns them.
+func (s *State) Remove(match func(*Item) bool) []*Item {
+	var removed []*Item
+	s.Items = slices.DeleteFunc(s.Items, func(item *Item) bool {
+		if match(item) {
+			removed = append(removed, item)
+			return true
+		}
+		return false
+	})
+
+	return removed
+}
+
+// Record counts the submission sent at the time. Submissions older than an hour are forgotten.
+func (s *State) Record(sent time.Time) {
+	s.Sent = slices.DeleteFunc(s.Sent, func(t time.Time) bool {
+		return sent.Sub(t) >= time.Hour
+	})
+	s.Sent = append(s.Sent, sent.UTC())
+}
+
+// Store keeps the queue in a json file.
+type Store struct {
+	Path string
+}
+
+// Load reads the queue. A missing file is an empty queue.
+func (s *Store) Load() (*State, error) {
+	state := &State{}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decoding apply queue %s: %w", s.Path, err)
+	}
+
+	return state, nil
+}
+
+func (s *Store) Save(state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(s.Path, data, 0o644)
+}
+
+// Update loads the queue, applies the change and saves it. The queue is shared with the worker,
+// so it is read again before every change.
+func (s *Store) Update(change func(*State)) error {
+	state, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	change(state)
+
+	return s.Save(state)
+}
@@ -0,0 +1,116 @@
+package queue
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schedule paces submissions.
+type Schedule struct {
+	// Interval is the minimal pause between submissions.
+	Interval time.Duration
+	// Jitter is the maximal random delay added to every pause.
+	Jitter time.Duration
+	// MaxPerHour limits submissions in a rolling hour. Zero means no limit.
+	MaxPerHour int
+	// From and To are the working hours as offsets from the local midnight. Equal values mean any time.
+	// The window may cross midnight, e.g. from 22:00 to 02:00.
+	From, To time.Duration
+}
+
+// ParseHours parses the working hours window like "09:00-19:00".
+func ParseHours(window string) (from, to time.Duration, err error) {
+	if strings.TrimSpace(window) == "" {
+		return 0, 0, nil
+	}
+
+	start, end, ok := strings.Cut(window, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("working hours %q must look like 09:00-19:00", window)
+	}
+
+	if from, err = parseClock(start); err != nil {
+		return 0, 0, err
+	}
+	if to, err = parseClock(end); err != nil {
+		return 0, 0, err
+	}
+
+	return from, to, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("parsing time of the day %q: %w", value, err)
+	}
+
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// Next returns the earliest time of the next submission after the sent ones.
+func (s Schedule) Next(now time.Time, sent []time.Time) time.Time {
+	next := now
+
+	if len(sent) > 0 {
+		last := slices.MaxFunc(sent, time.Time.Compare)
+		next = later(next, last.Add(s.Interval+s.jitter()))
+	}
+
+	if s.MaxPerHour > 0 {
+		recent := slices.DeleteFunc(slices.Clone(sent), func(t time.Time) bool {
+			return next.Sub(t) >= time.Hour
+		})
+		if len(recent) >= s.MaxPerHour {
+			slices.SortFunc(recent, time.Time.Compare)
+			next = later(next, recent[len(recent)-s.MaxPerHour].Add(time.Hour+s.jitter()))
+		}
+	}
+
+	if start := s.windowStart(next); !start.Equal(next) {
+		next = start.Add(s.jitter())
+	}
+
+	return next
+}
+
+// windowStart returns the time itself within the working hours or the start of the next window.
+func (s Schedule) windowStart(t time.Time) time.Time {
+	if s.From == s.To {
+		return t
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+
+	inside := offset >= s.From && offset < s.To
+	if s.From > s.To {
+		inside = offset >= s.From || offset < s.To
+	}
+	if inside {
+		return t
+	}
+
+	if offset < s.From {
+		return midnight.Add(s.From)
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()).Add(s.From)
+}
+
+func (s Schedule) jitter() time.Duration {
+	if s.Jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.Jitter)
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
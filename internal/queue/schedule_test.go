@@ -0,0 +1,109 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		window   string
+		from, to time.Duration
+		err      bool
+	}{
+		{window: ""},
+		{window: "09:00-19:30", from: 9 * time.Hour, to: 19*time.Hour + 30*time.Minute},
+		{window: " 22:00 - 02:00 ", from: 22 * time.Hour, to: 2 * time.Hour},
+		{window: "09:00", err: true},
+		{window: "9am-7pm", err: true},
+	}
+
+	for _, tt := range tests {
+		from, to, err := ParseHours(tt.window)
+		if (err != nil) != tt.err {
+			t.Fatalf("%q: unexpected error %v", tt.window, err)
+		}
+		if from != tt.from || to != tt.to {
+			t.Fatalf("%q: expected %s-%s, got %s-%s", tt.window, tt.from, tt.to, from, to)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2024, time.May, 6, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		sent     []time.Time
+		expected time.Time
+	}{
+		{
+			name:     "first submission",
+			schedule: Schedule{Interval: 5 * time.Minute},
+			now:      day(10, 0),
+			expected: day(10, 0),
+		},
+		{
+			name:     "interval after the last one",
+			schedule: Schedule{Interval: 5 * time.Minute},
+			now:      day(10, 0),
+			sent:     []time.Time{day(9, 50), day(9, 58)},
+			expected: day(10, 3),
+		},
+		{
+			name:     "hourly limit",
+			schedule: Schedule{Interval: time.Minute, MaxPerHour: 2},
+			now:      day(10, 0),
+			sent:     []time.Time{day(9, 20), day(9, 40)},
+			expected: day(10, 20),
+		},
+		{
+			name:     "before working hours",
+			schedule: Schedule{From: 9 * time.Hour, To: 19 * time.Hour},
+			now:      day(7, 30),
+			expected: day(9, 0),
+		},
+		{
+			name:     "after working hours",
+			schedule: Schedule{From: 9 * time.Hour, To: 19 * time.Hour},
+			now:      day(19, 0),
+			expected: day(33, 0),
+		},
+		{
+			name:     "window across midnight",
+			schedule: Schedule{From: 22 * time.Hour, To: 2 * time.Hour},
+			now:      day(1, 0),
+			expected: day(1, 0),
+		},
+		{
+			name:     "outside of the window across midnight",
+			schedule: Schedule{From: 22 * time.Hour, To: 2 * time.Hour},
+			now:      day(12, 0),
+			expected: day(22, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(tt.now, tt.sent); !got.Equal(tt.expected) {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScheduleJitter(t *testing.T) {
+	schedule := Schedule{Interval: time.Minute, Jitter: 30 * time.Second}
+	now := time.Now()
+
+	for range 100 {
+		next := schedule.Next(now, []time.Time{now})
+		if pause := next.Sub(now); pause < time.Minute || pause >= 90*time.Second {
+			t.Fatalf("pause %s is out of the interval with jitter", pause)
+		}
+	}
+}
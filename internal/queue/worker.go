@@ -0,0 +1,193 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/utils"
+)
+
+const (
+	// DefaultMaxAttempts is the number of submissions of an item before it fails.
+	DefaultMaxAttempts = 5
+	// DefaultBackoff is the pause before the first retry. It doubles with every attempt.
+	DefaultBackoff = 5 * time.Minute
+)
+
+// ErrHold is wrapped by submit errors that stop the account for the rest of the run, e.g. when its
+// daily quota is exhausted. Items of the account stay in the queue untouched.
+var ErrHold = errors.New("account is on hold")
+
+// Submit sends the application of the item.
+type Submit func(ctx context.Context, item *Item) error
+
+// Stats counts the outcomes of a worker run.
+type Stats struct {
+	Sent    int
+	Retried int
+	Failed  int
+	Held    int
+}
+
+// Worker submits queued applications one by one following the schedule.
+type Worker struct {
+	Store    *Store
+	Schedule Schedule
+	Submit   Submit
+	// Match limits the worker to some items, e.g. of one account. Nil means all items.
+	Match func(*Item) bool
+	// Temporary reports errors worth retrying. Other errors fail the item.
+	Temporary   func(error) bool
+	MaxAttempts int
+	Backoff     time.Duration
+	Logger      *zap.Logger
+
+	now  func() time.Time
+	wait func(context.Context, time.Duration) error
+}
+
+func NewWorker(store *Store, schedule Schedule, submit Submit, logger *zap.Logger) *Worker {
+	return &Worker{
+		Store:       store,
+		Schedule:    schedule,
+		Submit:      submit,
+		Temporary:   headhunter.IsTemporary,
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+		Logger:      logger,
+		now:         time.Now,
+		wait:        utils.WaitFor,
+	}
+}
+
+// Run submits items until nothing is left to submit or the context is done. Items waiting
+// for a retry are waited for, failed items and items of held accounts are left in the queue.
+func (w *Worker) Run(ctx context.Context) (Stats, error) {
+	var (
+		stats Stats
+		held  = make(map[string]bool)
+		// next is the paced time of the next submission. The queue is read again after waiting
+		// for it since items may be dropped meanwhile.
+		next time.Time
+	)
+
+	for {
+		state, err := w.Store.Load()
+		if err != nil {
+			return stats, err
+		}
+
+		item, retryAt := w.pick(state, held)
+		if item == nil {
+			if retryAt.IsZero() {
+				return stats, nil
+			}
+
+			w.Logger.Info("waiting for retries", zap.Time("next_attempt_at", retryAt))
+			if err := w.wait(ctx, retryAt.Sub(w.now())); err != nil {
+				return stats, err
+			}
+			continue
+		}
+
+		if next.IsZero() {
+			next = w.Schedule.Next(w.now(), state.Sent)
+		}
+		if pause := next.Sub(w.now()); pause > 0 {
+			w.Logger.Info("waiting for the next submission", zap.Time("at", next), zap.Duration("pause", pause.Round(time.Second)))
+			if err := w.wait(ctx, pause); err != nil {
+				return stats, err
+			}
+			continue
+		}
+		next = time.Time{}
+
+		err = w.Submit(ctx, item)
+		if err != nil && ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
+
+		logger := w.Logger.With(zap.String("account", item.Account), zap.String("vacancy_id", item.VacancyID))
+		if errors.Is(err, ErrHold) {
+			logger.Warn("account is held until the next run", zap.Error(err))
+			held[item.Account] = true
+			stats.Held++
+			continue
+		}
+
+		var failed bool
+		if err := w.Store.Update(func(state *State) {
+			failed = w.settle(state, item, err)
+		}); err != nil {
+			return stats, err
+		}
+
+		switch {
+		case err == nil:
+			logger.Info("application sent", zap.String("vacancy_name", item.VacancyName))
+			stats.Sent++
+		case failed:
+			logger.Warn("application failed", zap.Error(err))
+			stats.Failed++
+		default:
+			logger.Warn("application will be retried", zap.Error(err))
+			stats.Retried++
+		}
+	}
+}
+
+// pick returns the first due item or the earliest retry time when no item is due.
+func (w *Worker) pick(state *State, held map[string]bool) (*Item, time.Time) {
+	now := w.now()
+
+	var retryAt time.Time
+	for _, item := range state.Items {
+		if item.Failed || held[item.Account] || (w.Match != nil && !w.Match(item)) {
+			continue
+		}
+
+		if item.Due(now) {
+			return item, time.Time{}
+		}
+
+		if retryAt.IsZero() || item.NextAttemptAt.Before(retryAt) {
+			retryAt = item.NextAttemptAt
+		}
+	}
+
+	return nil, retryAt
+}
+
+// settle records the outcome of the submission and reports whether the item failed.
+func (w *Worker) settle(state *State, item *Item, err error) bool {
+	now := w.now()
+
+	if err == nil {
+		state.Record(now)
+		state.Remove(func(queued *Item) bool {
+			return queued.Account == item.Account && queued.VacancyID == item.VacancyID
+		})
+		return false
+	}
+
+	// The item may be dropped while it was submitted.
+	current := state.Find(item.Account, item.VacancyID)
+	if current == nil {
+		return true
+	}
+
+	current.Attempts++
+	current.LastError = err.Error()
+
+	if !w.Temporary(err) || current.Attempts >= w.MaxAttempts {
+		current.Failed = true
+		return true
+	}
+
+	current.NextAttemptAt = now.Add(w.Backoff << (current.Attempts - 1)).UTC()
+	return false
+}
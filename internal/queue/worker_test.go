@@ -0,0 +1,183 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// clock is moved forward by the waits of the worker instead of sleeping.
+type clock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func (c *clock) Wait(_ context.Context, d time.Duration) error {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func newTestWorker(t *testing.T, submit Submit, items ...*Item) (*Worker, *clock) {
+	t.Helper()
+
+	store := &Store{Path: filepath.Join(t.TempDir(), "queue.json")}
+	if err := store.Update(func(state *State) {
+		for _, item := range items {
+			if !state.Add(item) {
+				t.Fatalf("item %s/%s is added twice", item.Account, item.VacancyID)
+			}
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &clock{now: time.Date(2024, time.May, 6, 10, 0, 0, 0, time.Local)}
+
+	worker := NewWorker(store, Schedule{Interval: 3 * time.Minute}, submit, zap.NewNop())
+	worker.now, worker.wait = c.Now, c.Wait
+
+	return worker, c
+}
+
+func item(account, vacancy string) *Item {
+	return NewItem(account, &headhunter.Vacancy{ID: vacancy}, &headhunter.Resume{ID: "r"}, "Hello!")
+}
+
+func TestWorker(t *testing.T) {
+	var (
+		submitted []string
+		attempts  = make(map[string]int)
+	)
+
+	submit := func(_ context.Context, item *Item) error {
+		attempts[item.VacancyID]++
+		submitted = append(submitted, item.Account+"/"+item.VacancyID)
+
+		switch item.VacancyID {
+		case "flaky":
+			if attempts["flaky"] < 3 {
+				return &headhunter.StatusError{Code: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+			}
+		case "down":
+			return &headhunter.StatusError{Code: http.StatusBadGateway, Status: "502 Bad Gateway"}
+		case "applied":
+			return &headhunter.StatusError{Code: http.StatusForbidden, Status: "403 Forbidden"}
+		case "over-quota":
+			return errors.Join(ErrHold, errors.New("daily quota is exhausted"))
+		}
+
+		return nil
+	}
+
+	worker, c := newTestWorker(t, submit,
+		item("alice", "1"),
+		item("alice", "flaky"),
+		item("alice", "applied"),
+		item("bob", "over-quota"),
+		item("bob", "2"),
+		item("alice", "down"),
+		item("alice", "3"),
+	)
+	worker.MaxAttempts = 2
+	worker.Backoff = time.Minute
+
+	// The retry of flaky fails for the second time.
+	stats, err := worker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats != (Stats{Sent: 2, Retried: 2, Failed: 3, Held: 1}) {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	expected := []string{"alice/1", "alice/flaky", "alice/applied", "bob/over-quota", "alice/down", "alice/3", "alice/flaky", "alice/down"}
+	if len(submitted) != len(expected) {
+		t.Fatalf("unexpected submissions: %v", submitted)
+	}
+	for i := range expected {
+		if submitted[i] != expected[i] {
+			t.Fatalf("unexpected submissions: %v", submitted)
+		}
+	}
+
+	for _, wait := range c.waits {
+		if wait < time.Minute {
+			t.Fatalf("submissions are not paced: %v", c.waits)
+		}
+	}
+
+	state, err := worker.Store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	left := make(map[string]*Item)
+	for _, item := range state.Items {
+		left[item.VacancyID] = item
+	}
+
+	if len(left) != 5 || !left["flaky"].Failed || !left["down"].Failed || !left["applied"].Failed {
+		t.Fatalf("unexpected items left: %+v", state.Items)
+	}
+	if left["applied"].Attempts != 1 || left["applied"].LastError != "bad status: 403 Forbidden" {
+		t.Fatalf("permanent errors must not be retried: %+v", left["applied"])
+	}
+	if left["over-quota"].Attempts != 0 || left["2"] == nil || left["2"].Failed {
+		t.Fatalf("items of the held account must stay untouched: %+v %+v", left["over-quota"], left["2"])
+	}
+	if len(state.Sent) != 2 {
+		t.Fatalf("expected 2 submissions recorded, got %v", state.Sent)
+	}
+}
+
+func TestWorkerMatch(t *testing.T) {
+	var submitted []string
+	worker, _ := newTestWorker(t, func(_ context.Context, item *Item) error {
+		submitted = append(submitted, item.Account+"/"+item.VacancyID)
+		return nil
+	}, item("alice", "1"), item("bob", "1"), item("alice", "2"))
+
+	worker.Match = func(item *Item) bool { return item.Account == "alice" }
+
+	if _, err := worker.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(submitted) != 2 || submitted[0] != "alice/1" || submitted[1] != "alice/2" {
+		t.Fatalf("unexpected submissions: %v", submitted)
+	}
+
+	state, _ := worker.Store.Load()
+	if len(state.Items) != 1 || state.Items[0].Account != "bob" {
+		t.Fatalf("unexpected items left: %+v", state.Items)
+	}
+}
+
+func TestWorkerCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	worker, _ := newTestWorker(t, func(ctx context.Context, _ *Item) error {
+		cancel()
+		return ctx.Err()
+	}, item("alice", "1"))
+
+	if _, err := worker.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+
+	state, _ := worker.Store.Load()
+	if len(state.Items) != 1 || state.Items[0].Attempts != 0 {
+		t.Fatalf("interrupted item must stay untouched: %+v", state.Items)
+	}
+}
@@ -0,0 +1,239 @@
+// Package quota keeps the daily budget of applications per account. hh.ru allows around 200
+// negotiations in 24 hours, applications over the budget are carried over to the next runs.
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+	"github.com/spigell/hh-responder/internal/utils"
+)
+
+const (
+	// DefaultDailyLimit is the number of applications hh.ru accepts from an account in 24 hours.
+	DefaultDailyLimit = 200
+	// Window is the rolling period the limit applies to.
+	Window = 24 * time.Hour
+	// DefaultCarriedAge is the longest search period of hh.ru. Vacancies carried over longer are dropped.
+	DefaultCarriedAge = 30 * 24 * time.Hour
+)
+
+// Account is the budget state of an account.
+type Account struct {
+	// Applications are the times of applications sent within the window.
+	Applications []time.Time `json:"applications"`
+	// ExhaustedUntil is set when hh.ru rejected an application for the limit before the local budget ran out.
+	ExhaustedUntil time.Time `json:"exhausted_until,omitzero"`
+	// Carried are vacancies left over the budget. They are offered first in the next runs.
+	Carried []*Carried `json:"carried,omitempty"`
+}
+
+// Carried is a vacancy left over the budget.
+type Carried struct {
+	*headhunter.Vacancy
+	// CarriedAt is the time the vacancy was carried over first.
+	CarriedAt time.Time `json:"carried_at"`
+}
+
+type State struct {
+	Accounts map[string]*Account `json:"accounts"`
+}
+
+// Account returns the state of the account creating it when missing.
+func (s *State) Account(name string) *Account {
+	if s.Accounts == nil {
+		s.Accounts = make(map[string]*Account)
+	}
+
+	account, ok := s.Accounts[name]
+	if !ok {
+		account = &Account{}
+		s.Accounts[name] = account
+	}
+
+	return account
+}
+
+// Remaining returns the number of applications allowed now. Applications older than the window are forgotten.
+func (a *Account) Remaining(limit int, now time.Time) int {
+	if limit <= 0 {
+		limit = DefaultDailyLimit
+	}
+
+	a.Applications = slices.DeleteFunc(a.Applications, func(sent time.Time) bool {
+		return now.Sub(sent) >= Window
+	})
+
+	if now.Before(a.ExhaustedUntil) {
+		return 0
+	}
+
+	return max(limit-len(a.Applications), 0)
+}
+
+// Record counts the application sent at the time.
+func (a *Account) Record(sent time.Time) {
+	a.Applications = append(a.Applications, sent.UTC())
+}
+
+// Exhaust stops applications until the oldest one in the window expires, or for the whole window
+// when none are known. It is used when hh.ru reports the limit the local state is not aware of.
+func (a *Account) Exhaust(now time.Time) {
+	until := now.Add(Window)
+	if len(a.Applications) > 0 {
+		until = slices.MinFunc(a.Applications, func(x, y time.Time) int { return x.Compare(y) }).Add(Window)
+	}
+
+	a.ExhaustedUntil = until.UTC()
+}
+
+// Carry replaces the carried vacancies. Vacancies carried over before keep their time.
+func (a *Account) Carry(vacancies []*headhunter.Vacancy, now time.Time) {
+	since := make(map[string]time.Time, len(a.Carried))
+	for _, carried := range a.Carried {
+		if carried.Vacancy != nil {
+			since[carried.ID] = carried.CarriedAt
+		}
+	}
+
+	a.Carried = make([]*Carried, 0, len(vacancies))
+	for _, vacancy := range vacancies {
+		at, ok := since[vacancy.ID]
+		if !ok {
+			at = now.UTC()
+		}
+		a.Carried = append(a.Carried, &Carried{Vacancy: vacancy, CarriedAt: at})
+	}
+}
+
+// Expire drops vacancies carried over at least maxAge ago and returns their number. They are
+// likely closed by now or out of the search period.
+func (a *Account) Expire(maxAge time.Duration, now time.Time) int {
+	before := len(a.Carried)
+	a.Carried = slices.DeleteFunc(a.Carried, func(carried *Carried) bool {
+		return carried.Vacancy == nil || now.Sub(carried.CarriedAt) >= maxAge
+	})
+
+	return before - len(a.Carried)
+}
+
+// Uncarry drops the vacancy handled outside of the runs from the carried ones. It reports whether
+// the vacancy was carried.
+func (a *Account) Uncarry(id string) bool {
+	before := len(a.Carried)
+	a.Carried = slices.DeleteFunc(a.Carried, func(carried *Carried) bool {
+		return carried.Vacancy != nil && carried.ID == id
+	})
+
+	return len(a.Carried) < before
+}
+
+// CarriedVacancies returns the carried vacancies.
+func (a *Account) CarriedVacancies() []*headhunter.Vacancy {
+	vacancies := make([]*headhunter.Vacancy, 0, len(a.Carried))
+	for _, carried := range a.Carried {
+		if carried.Vacancy != nil {
+			vacancies = append(vacancies, carried.Vacancy)
+		}
+	}
+
+	return vacancies
+}
+
+// Prioritize orders vacancies for the budget: vacancies of favourite employers go first, then
+// the ones with the higher AI score. The order of the search is kept otherwise.
+func Prioritize(vacancies []*headhunter.Vacancy, favorites []string) {
+	slices.SortStableFunc(vacancies, func(a, b *headhunter.Vacancy) int {
+		favoriteA, favoriteB := slices.Contains(favorites, a.Employer.ID), slices.Contains(favorites, b.Employer.ID)
+		if favoriteA != favoriteB {
+			if favoriteA {
+				return -1
+			}
+			return 1
+		}
+
+		return -compareScores(a, b)
+	})
+}
+
+func compareScores(a, b *headhunter.Vacancy) int {
+	score := func(v *headhunter.Vacancy) float64 {
+		if v.AI == nil || v.AI.Error != "" {
+			return -1
+		}
+		return v.AI.Score
+	}
+
+	switch scoreA, scoreB := score(a), score(b); {
+	case scoreA < scoreB:
+		return -1
+	case scoreA > scoreB:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Split returns vacancies fitting the budget and the overflow to carry over.
+func Split(vacancies []*headhunter.Vacancy, budget int) (fit, overflow []*headhunter.Vacancy) {
+	if budget >= len(vacancies) {
+		return vacancies, nil
+	}
+
+	budget = max(budget, 0)
+	return vacancies[:budget], vacancies[budget:]
+}
+
+// Store keeps the state in a json file.
+type Store struct {
+	Path string
+}
+
+// Load reads the state. A missing file is an empty state.
+func (s *Store) Load() (*State, error) {
+	state := &State{Accounts: make(map[string]*Account)}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decoding quota state %s: %w", s.Path, err)
+	}
+
+	if state.Accounts == nil {
+		state.Accounts = make(map[string]*Account)
+	}
+
+	return state, nil
+}
+
+func (s *Store) Save(state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(s.Path, data, 0o644)
+}
+
+// Update loads the state, applies the change to the account and saves it. The state is shared by accounts and runs.
+func (s *Store) Update(account string, change func(*Account)) error {
+	state, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	change(state.Account(account))
+
+	return s.Save(state)
+}
@@ -0,0 +1,132 @@
+package quota
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+func TestRemaining(t *testing.T) {
+	now := time.Now()
+	account := &Account{}
+
+	if got := account.Remaining(0, now); got != DefaultDailyLimit {
+		t.Fatalf("expected the default limit, got %d", got)
+	}
+
+	account.Record(now.Add(-25 * time.Hour))
+	account.Record(now.Add(-23 * time.Hour))
+	account.Record(now.Add(-time.Hour))
+
+	if got := account.Remaining(3, now); got != 1 {
+		t.Fatalf("expected 1 application left, got %d", got)
+	}
+	if len(account.Applications) != 2 {
+		t.Fatalf("applications out of the window must be forgotten, got %v", account.Applications)
+	}
+	if got := account.Remaining(1, now); got != 0 {
+		t.Fatalf("expected nothing left over the limit, got %d", got)
+	}
+
+	// hh.ru reported the limit: nothing is allowed until the oldest application expires.
+	account.Exhaust(now)
+	if got := account.Remaining(10, now); got != 0 {
+		t.Fatalf("expected exhausted quota, got %d", got)
+	}
+	if got := account.Remaining(10, now.Add(90*time.Minute)); got != 9 {
+		t.Fatalf("expected the quota back after the oldest application expired, got %d", got)
+	}
+}
+
+func vacancy(id, employer string, score float64) *headhunter.Vacancy {
+	v := &headhunter.Vacancy{ID: id}
+	v.Employer.ID = employer
+	if score >= 0 {
+		v.AI = &headhunter.AIAssessment{Fit: true, Score: score}
+	}
+	return v
+}
+
+func TestPrioritizeAndSplit(t *testing.T) {
+	vacancies := []*headhunter.Vacancy{
+		vacancy("1", "10", -1),
+		vacancy("2", "20", 0.7),
+		vacancy("3", "30", 0.9),
+		vacancy("4", "40", 0.6),
+		vacancy("5", "50", 0.7),
+	}
+
+	Prioritize(vacancies, []string{"40"})
+
+	if ids := strings.Join(headhunter.IDs(vacancies), ","); ids != "4,3,2,5,1" {
+		t.Fatalf("unexpected order: %s", ids)
+	}
+
+	fit, overflow := Split(vacancies, 2)
+	if len(fit) != 2 || len(overflow) != 3 || overflow[0].ID != "2" {
+		t.Fatalf("unexpected split: %v %v", headhunter.IDs(fit), headhunter.IDs(overflow))
+	}
+
+	if fit, overflow := Split(vacancies, 10); len(fit) != 5 || overflow != nil {
+		t.Fatalf("everything must fit the budget")
+	}
+	if fit, overflow := Split(vacancies, 0); len(fit) != 0 || len(overflow) != 5 {
+		t.Fatalf("everything must be carried over without budget")
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "quota.json")}
+	sent := time.Now().Add(-time.Hour)
+
+	err := store.Update("main", func(account *Account) {
+		account.Record(sent)
+		account.Carry([]*headhunter.Vacancy{vacancy("1", "10", 0.8)}, time.Now())
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	account := state.Account("main")
+	if account.Remaining(5, time.Now()) != 4 {
+		t.Fatalf("unexpected applications: %v", account.Applications)
+	}
+	if len(account.Carried) != 1 || account.Carried[0].AI.Score != 0.8 || account.Carried[0].CarriedAt.IsZero() {
+		t.Fatalf("carried vacancies must keep the assessment: %+v", account.Carried)
+	}
+	if len(state.Account("second").Applications) != 0 {
+		t.Fatalf("accounts must be separate")
+	}
+}
+
+func TestCarryAndExpire(t *testing.T) {
+	now := time.Now()
+	account := &Account{}
+
+	account.Carry([]*headhunter.Vacancy{vacancy("1", "10", -1), vacancy("2", "10", -1)}, now.Add(-48*time.Hour))
+	account.Carry([]*headhunter.Vacancy{vacancy("2", "10", -1), vacancy("3", "10", -1)}, now)
+
+	if len(account.Carried) != 2 || !account.Carried[0].CarriedAt.Equal(now.Add(-48*time.Hour)) || !account.Carried[1].CarriedAt.Equal(now) {
+		t.Fatalf("vacancies carried again must keep their time: %+v", account.Carried)
+	}
+
+	if expired := account.Expire(24*time.Hour, now); expired != 1 {
+		t.Fatalf("expected 1 expired vacancy, got %d", expired)
+	}
+
+	if carried := account.CarriedVacancies(); len(carried) != 1 || carried[0].ID != "3" {
+		t.Fatalf("unexpected carried vacancies: %+v", carried)
+	}
+
+	if account.Uncarry("1") || !account.Uncarry("3") || len(account.Carried) != 0 {
+		t.Fatalf("expected only the carried vacancy dropped: %+v", account.Carried)
+	}
+}
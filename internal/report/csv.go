@@ -0,0 +1,68 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+var csvHeader = []string{
+	"id", "name", "url",
+	"employer_id", "employer",
+	"area", "schedule", "experience", "employment",
+	"salary_from", "salary_to", "salary_currency", "salary_gross",
+	"published_at",
+	"ai_fit", "ai_score", "ai_reason", "ai_message", "ai_error", "ai_resume",
+	"dropped_by", "drop_reason",
+}
+
+// writeCSV writes one row per vacancy. Dropped vacancies follow the kept ones with the filter and the reason set.
+func writeCSV(w io.Writer, vacancies *headhunter.Vacancies, dropped []Dropped) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, v := range vacancies.Items {
+		if err := writer.Write(append(csvRow(v), "", "")); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range dropped {
+		if err := writer.Write(append(csvRow(d.Vacancy), d.Filter, d.Reason)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvRow(v *headhunter.Vacancy) []string {
+	row := []string{
+		v.ID, v.Name, v.AlternateURL,
+		v.Employer.ID, v.Employer.Name,
+		v.Area.Name, v.Schedule.Name, v.Experience.Name, v.Employment.Name,
+		optionalInt(v.Salary.From), optionalInt(v.Salary.To), v.Salary.Currency, strconv.FormatBool(v.Salary.Gross),
+		v.PublishedAt,
+	}
+
+	if v.AI != nil {
+		row = append(row, strconv.FormatBool(v.AI.Fit), aiScore(v), v.AI.Reason, v.AI.Message, v.AI.Error, v.AI.ResumeTitle)
+	} else {
+		row = append(row, "", "", "", "", "", "")
+	}
+
+	return row
+}
+
+func optionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
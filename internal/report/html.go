@@ -0,0 +1,78 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"score":  aiScore,
+	"reason": aiReason,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>hh-responder report</title>
+<style>
+body { font-family: sans-serif; margin: 1.5em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { border-bottom: 1px solid #ddd; padding: .4em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+.muted { color: #888; }
+</style>
+</head>
+<body>
+<h1>Vacancies ({{ .Total }})</h1>
+<p class="muted">Generated at {{ .GeneratedAt.Format "2006-01-02 15:04 MST" }}</p>
+{{ range .Groups }}
+<h2>{{ .Name }} <span class="muted">({{ len .Vacancies }})</span></h2>
+<table>
+<thead><tr><th>Vacancy</th><th>Area</th><th>Schedule</th><th>Salary</th><th>AI score</th><th>AI reason</th><th>Message</th></tr></thead>
+<tbody>
+{{ range .Vacancies }}<tr>
+<td>{{ if .AlternateURL }}<a href="{{ .AlternateURL }}">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}</td>
+<td>{{ .Area.Name }}</td>
+<td>{{ .Schedule.Name }}</td>
+<td>{{ .SalaryString }}</td>
+<td>{{ score . }}</td>
+<td>{{ reason . }}</td>
+<td>{{ if .AI }}{{ .AI.Message }}{{ end }}</td>
+</tr>
+{{ end }}</tbody>
+</table>
+{{ end }}
+{{ if .Dropped }}
+<h1>Dropped ({{ len .Dropped }})</h1>
+<table>
+<thead><tr><th>Vacancy</th><th>Employer</th><th>Filter</th><th>Reason</th></tr></thead>
+<tbody>
+{{ range .Dropped }}<tr>
+<td>{{ if .Vacancy.AlternateURL }}<a href="{{ .Vacancy.AlternateURL }}">{{ .Vacancy.Name }}</a>{{ else }}{{ .Vacancy.Name }}{{ end }}</td>
+<td>{{ .Vacancy.Employer.Name }}</td>
+<td>{{ .Filter }}</td>
+<td>{{ .Reason }}</td>
+</tr>
+{{ end }}</tbody>
+</table>
+{{ end }}
+</body>
+</html>
+`))
+
+// writeHTML writes a standalone HTML page with vacancies grouped by employer and the dropped ones.
+func writeHTML(w io.Writer, vacancies *headhunter.Vacancies, dropped []Dropped) error {
+	return htmlTemplate.Execute(w, struct {
+		Total       int
+		GeneratedAt time.Time
+		Groups      []*employerGroup
+		Dropped     []Dropped
+	}{
+		Total:       vacancies.Len(),
+		GeneratedAt: time.Now(),
+		Groups:      groupByEmployer(vacancies),
+		Dropped:     dropped,
+	})
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// writeMarkdown writes a table of vacancies per employer and a table of dropped vacancies.
+func writeMarkdown(w io.Writer, vacancies *headhunter.Vacancies, dropped []Dropped) error {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "# Vacancies (%d)\n", vacancies.Len())
+
+	for _, group := range groupByEmployer(vacancies) {
+		fmt.Fprintf(&builder, "\n## %s (%d)\n\n", markdownCell(group.Name), len(group.Vacancies))
+		builder.WriteString("| Vacancy | Area | Schedule | Salary | AI score | AI reason |\n")
+		builder.WriteString("|---|---|---|---|---|---|\n")
+
+		for _, v := range group.Vacancies {
+			fmt.Fprintf(&builder, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownLink(v.Name, v.AlternateURL),
+				markdownCell(v.Area.Name),
+				markdownCell(v.Schedule.Name),
+				markdownCell(v.SalaryString()),
+				aiScore(v),
+				markdownCell(aiReason(v)),
+			)
+		}
+	}
+
+	if len(dropped) > 0 {
+		fmt.Fprintf(&builder, "\n# Dropped (%d)\n\n", len(dropped))
+		builder.WriteString("| Vacancy | Employer | Filter | Reason |\n")
+		builder.WriteString("|---|---|---|---|\n")
+
+		for _, d := range dropped {
+			fmt.Fprintf(&builder, "| %s | %s | %s | %s |\n",
+				markdownLink(d.Vacancy.Name, d.Vacancy.AlternateURL),
+				markdownCell(d.Vacancy.Employer.Name),
+				markdownCell(d.Filter),
+				markdownCell(d.Reason),
+			)
+		}
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func markdownLink(name, url string) string {
+	name = markdownCell(name)
+	if url == "" {
+		return name
+	}
+	return fmt.Sprintf("[%s](%s)", name, url)
+}
+
+// markdownCell keeps the value on one line and escapes table separators.
+func markdownCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer("|", "\\|", "[", "\\[", "]", "\\]").Replace(s)
+}
@@ -0,0 +1,130 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// Format is an export format of the vacancies report.
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat returns the requested format. When it is empty the format is guessed by the file extension.
+func ParseFormat(format, path string) (Format, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	switch format {
+	case "csv":
+		return FormatCSV, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "htm", "html":
+		return FormatHTML, nil
+	case "":
+		return "", fmt.Errorf("export format is not set and can't be guessed from %q", path)
+	default:
+		return "", fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// Dropped is a vacancy removed by a filter with the reason.
+type Dropped struct {
+	Vacancy *headhunter.Vacancy
+	Filter  string
+	Reason  string
+}
+
+// Write renders vacancies and, when given, the dropped ones in the given format.
+func Write(w io.Writer, format Format, vacancies *headhunter.Vacancies, dropped []Dropped) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, vacancies, dropped)
+	case FormatMarkdown:
+		return writeMarkdown(w, vacancies, dropped)
+	case FormatHTML:
+		return writeHTML(w, vacancies, dropped)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// WriteFile renders vacancies in the given format to the file replacing its content.
+func WriteFile(path string, format Format, vacancies *headhunter.Vacancies, dropped []Dropped) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := Write(file, format, vacancies, dropped); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+type employerGroup struct {
+	Name      string
+	ID        string
+	Vacancies []*headhunter.Vacancy
+}
+
+// groupByEmployer groups vacancies by employer sorted by employer name.
+func groupByEmployer(vacancies *headhunter.Vacancies) []*employerGroup {
+	groups := make(map[string]*employerGroup)
+	for _, vacancy := range vacancies.Items {
+		key := vacancy.Employer.ID + "/" + vacancy.Employer.Name
+		group, ok := groups[key]
+		if !ok {
+			group = &employerGroup{Name: vacancy.Employer.Name, ID: vacancy.Employer.ID}
+			groups[key] = group
+		}
+		group.Vacancies = append(group.Vacancies, vacancy)
+	}
+
+	result := make([]*employerGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+func aiScore(vacancy *headhunter.Vacancy) string {
+	if vacancy.AI == nil || vacancy.AI.Error != "" {
+		return ""
+	}
+	return strconv.FormatFloat(vacancy.AI.Score, 'f', 2, 64)
+}
+
+func aiReason(vacancy *headhunter.Vacancy) string {
+	if vacancy.AI == nil {
+		return ""
+	}
+	if vacancy.AI.Error != "" {
+		return "error: " + vacancy.AI.Error
+	}
+	return vacancy.AI.Reason
+}
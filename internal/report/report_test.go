@@ -0,0 +1,218 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+func testVacancies() *headhunter.Vacancies {
+	first := &headhunter.Vacancy{ID: "1", Name: "Go | Developer", AlternateURL: "https://hh.ru/vacancy/1"}
+	first.Employer.ID = "e2"
+	first.Employer.Name = "Globex"
+	first.Area.Name = "Moscow"
+	first.Salary.From = 100
+	first.Salary.Currency = "RUR"
+	first.AI = &headhunter.AIAssessment{Fit: true, Score: 0.875, Reason: "Good <match>", Message: "Hello"}
+
+	second := &headhunter.Vacancy{ID: "2", Name: "SRE"}
+	second.Employer.ID = "e1"
+	second.Employer.Name = "Acme"
+	second.Schedule.Name = "Remote"
+	second.AI = &headhunter.AIAssessment{Error: "quota exceeded"}
+
+	return &headhunter.Vacancies{Items: []*headhunter.Vacancy{first, second}}
+}
+
+func testDropped() []Dropped {
+	vacancy := &headhunter.Vacancy{ID: "3", Name: "PHP Developer"}
+	vacancy.Employer.Name = "Initech"
+
+	return []Dropped{{Vacancy: vacancy, Filter: "employers", Reason: "employer 3331116 in exclude list"}}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		path   string
+		expect Format
+		err    bool
+	}{
+		{format: "CSV", expect: FormatCSV},
+		{format: "md", expect: FormatMarkdown},
+		{path: "report.html", expect: FormatHTML},
+		{path: "/tmp/report.markdown", expect: FormatMarkdown},
+		{format: "csv", path: "report.html", expect: FormatCSV},
+		{path: "report", err: true},
+		{format: "pdf", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.format, tt.path)
+		if (err != nil) != tt.err {
+			t.Fatalf("%q/%q: unexpected error: %v", tt.format, tt.path, err)
+		}
+		if got != tt.expect {
+			t.Fatalf("%q/%q: expected %q, got %q", tt.format, tt.path, tt.expect, got)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, testVacancies(), testDropped()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+
+	if len(rows) != 4 {
+		t.Fatalf("expected header and 3 rows, got %d", len(rows))
+	}
+
+	record := make(map[string]string)
+	for idx, column := range rows[0] {
+		record[column] = rows[1][idx]
+	}
+
+	if record["name"] != "Go | Developer" || record["salary_from"] != "100" || record["salary_to"] != "" {
+		t.Fatalf("unexpected vacancy fields: %v", record)
+	}
+	if record["ai_score"] != "0.88" || record["ai_fit"] != "true" || record["area"] != "Moscow" {
+		t.Fatalf("unexpected ai fields: %v", record)
+	}
+	if record["dropped_by"] != "" {
+		t.Fatalf("kept vacancy has drop fields: %v", record)
+	}
+
+	dropped := rows[3]
+	if dropped[0] != "3" || dropped[len(dropped)-2] != "employers" || dropped[len(dropped)-1] != "employer 3331116 in exclude list" {
+		t.Fatalf("unexpected dropped row: %v", dropped)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatMarkdown, testVacancies(), testDropped()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Index(out, "## Acme") > strings.Index(out, "## Globex") {
+		t.Fatalf("expected employers to be sorted:\n%s", out)
+	}
+	if !strings.Contains(out, "| [Go \\| Developer](https://hh.ru/vacancy/1) | Moscow |  | from 100 RUR | 0.88 | Good <match> |") {
+		t.Fatalf("unexpected vacancy row:\n%s", out)
+	}
+	if !strings.Contains(out, "error: quota exceeded") {
+		t.Fatalf("expected ai error:\n%s", out)
+	}
+	if !strings.Contains(out, "# Dropped (1)") || !strings.Contains(out, "| PHP Developer | Initech | employers | employer 3331116 in exclude list |") {
+		t.Fatalf("expected dropped vacancies:\n%s", out)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatHTML, testVacancies(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "Good &lt;match&gt;") {
+		t.Fatalf("expected escaped reason:\n%s", out)
+	}
+	if !strings.Contains(out, `<a href="https://hh.ru/vacancy/1">Go | Developer</a>`) {
+		t.Fatalf("expected vacancy link:\n%s", out)
+	}
+	if !strings.Contains(out, "<h2>Acme") || !strings.Contains(out, "<h2>Globex") {
+		t.Fatalf("expected employer groups:\n%s", out)
+	}
+	if strings.Contains(out, "Dropped") {
+		t.Fatalf("unexpected dropped section without dropped vacancies:\n%s", out)
+	}
+}
+
+func TestResult(t *testing.T) {
+	run := NewRun("test")
+	result := run.AddAccount("main", nil)
+	vacancies := testVacancies()
+
+	result.Found = 3
+	result.SetKept(vacancies)
+	result.AddApplication(vacancies.Items[0], &headhunter.Resume{ID: "r1"}, nil)
+	result.AddApplication(vacancies.Items[1], nil, errors.New("bad status: 403 Forbidden"))
+	result.Finish(nil)
+	run.AddAccount("second", nil).Finish(errors.New("loading headhunter token: file is empty"))
+	run.Finish(nil)
+
+	var buf bytes.Buffer
+	if err := run.Write(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decodedRun Run
+	if err := json.Unmarshal(buf.Bytes(), &decodedRun); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decodedRun.Version != "test" || len(decodedRun.Accounts) != 2 || decodedRun.Accounts[1].Error == "" {
+		t.Fatalf("unexpected run: %+v", decodedRun)
+	}
+
+	decoded := decodedRun.Accounts[0]
+	if decoded.Account != "main" {
+		t.Fatalf("unexpected account: %s", decoded.Account)
+	}
+
+	if len(decoded.Kept) != vacancies.Len() || decoded.Dropped == nil {
+		t.Fatalf("unexpected vacancies: %+v", decoded)
+	}
+	if decoded.Applications[0].Status != ApplicationStatusApplied || decoded.Applications[0].ResumeID != "r1" {
+		t.Fatalf("unexpected application: %+v", decoded.Applications[0])
+	}
+	if decoded.Applications[1].Status != ApplicationStatusFailed || !strings.Contains(decoded.Applications[1].Error, "403") {
+		t.Fatalf("unexpected failed application: %+v", decoded.Applications[1])
+	}
+	if decoded.FinishedAt.Before(decoded.StartedAt) {
+		t.Fatalf("unexpected timestamps: %v %v", decoded.StartedAt, decoded.FinishedAt)
+	}
+}
+
+func TestReadRunFile(t *testing.T) {
+	run := NewRun("test")
+	result := run.AddAccount("main", nil)
+	vacancies := testVacancies()
+	result.SetKept(&headhunter.Vacancies{Items: vacancies.Items[1:]})
+	result.AddApplication(vacancies.Items[0], nil, nil)
+	result.AddApplication(vacancies.Items[1], nil, nil)
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := run.WriteFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	read, err := ReadRunFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{vacancies.Items[0].ID}
+	for _, vacancy := range vacancies.Items[1:] {
+		expected = append(expected, vacancy.ID)
+	}
+	if got := read.Accounts[0].VacancyIDs(); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected ids %v, got %v", expected, got)
+	}
+}
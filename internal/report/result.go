@@ -0,0 +1,224 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+const (
+	ApplicationStatusApplied = "applied"
+	ApplicationStatusFailed  = "failed"
+)
+
+// Run is a machine-readable summary of a single run over all accounts.
+type Run struct {
+	Version    string    `json:"version"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Accounts   []*Result `json:"accounts"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Result is the outcome of the run for a single account.
+type Result struct {
+	Account      string                   `json:"account"`
+	StartedAt    time.Time                `json:"started_at"`
+	FinishedAt   time.Time                `json:"finished_at"`
+	Search       *headhunter.SearchParams `json:"search"`
+	Resumes      []*headhunter.Resume     `json:"resumes,omitempty"`
+	Found        int                      `json:"found"`
+	Steps        []ResultStep             `json:"steps"`
+	Kept         []ResultVacancy          `json:"kept"`
+	Dropped      []ResultVacancy          `json:"dropped"`
+	Applications []Application            `json:"applications"`
+	Carried      []ResultVacancy          `json:"carried,omitempty"`
+	Queued       []ResultVacancy          `json:"queued,omitempty"`
+	Error        string                   `json:"error,omitempty"`
+}
+
+// ResultStep is the outcome of a single filter.
+type ResultStep struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Initial int    `json:"initial"`
+	Dropped int    `json:"dropped"`
+	Left    int    `json:"left"`
+}
+
+// ResultVacancy is a short vacancy description. Filter and Reason are set for dropped vacancies only.
+type ResultVacancy struct {
+	ID         string                   `json:"id"`
+	Name       string                   `json:"name"`
+	URL        string                   `json:"url,omitempty"`
+	EmployerID string                   `json:"employer_id,omitempty"`
+	Employer   string                   `json:"employer,omitempty"`
+	Area       string                   `json:"area,omitempty"`
+	Salary     string                   `json:"salary,omitempty"`
+	AI         *headhunter.AIAssessment `json:"ai,omitempty"`
+	Filter     string                   `json:"filter,omitempty"`
+	Reason     string                   `json:"reason,omitempty"`
+}
+
+// Application is the outcome of applying to a vacancy.
+type Application struct {
+	VacancyID   string    `json:"vacancy_id"`
+	VacancyName string    `json:"vacancy_name,omitempty"`
+	ResumeID    string    `json:"resume_id,omitempty"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// NewRun starts a summary of the run started now.
+func NewRun(version string) *Run {
+	return &Run{
+		Version:   version,
+		StartedAt: time.Now().UTC(),
+		Accounts:  []*Result{},
+	}
+}
+
+// AddAccount starts a result for the account.
+func (r *Run) AddAccount(account string, search *headhunter.SearchParams) *Result {
+	result := &Result{
+		Account:      account,
+		StartedAt:    time.Now().UTC(),
+		Search:       search,
+		Steps:        []ResultStep{},
+		Kept:         []ResultVacancy{},
+		Dropped:      []ResultVacancy{},
+		Applications: []Application{},
+	}
+
+	r.Accounts = append(r.Accounts, result)
+	return result
+}
+
+// Finish marks the run as finished with an optional error.
+func (r *Run) Finish(err error) {
+	r.FinishedAt = time.Now().UTC()
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// NewResultVacancy converts the vacancy to its short description.
+func NewResultVacancy(vacancy *headhunter.Vacancy) ResultVacancy {
+	return ResultVacancy{
+		ID:         vacancy.ID,
+		Name:       vacancy.Name,
+		URL:        vacancy.AlternateURL,
+		EmployerID: vacancy.Employer.ID,
+		Employer:   vacancy.Employer.Name,
+		Area:       vacancy.Area.Name,
+		Salary:     vacancy.SalaryString(),
+		AI:         vacancy.AI,
+	}
+}
+
+// SetKept replaces the list of vacancies left after filtering.
+func (r *Result) SetKept(vacancies *headhunter.Vacancies) {
+	r.Kept = make([]ResultVacancy, 0, vacancies.Len())
+	for _, vacancy := range vacancies.Items {
+		r.Kept = append(r.Kept, NewResultVacancy(vacancy))
+	}
+}
+
+// AddApplication records the outcome of applying to the vacancy.
+func (r *Result) AddApplication(vacancy *headhunter.Vacancy, resume *headhunter.Resume, err error) {
+	application := Application{
+		VacancyID:   vacancy.ID,
+		VacancyName: vacancy.Name,
+		Status:      ApplicationStatusApplied,
+		At:          time.Now().UTC(),
+	}
+
+	if resume != nil {
+		application.ResumeID = resume.ID
+	}
+
+	if err != nil {
+		application.Status = ApplicationStatusFailed
+		application.Error = err.Error()
+	}
+
+	r.Applications = append(r.Applications, application)
+}
+
+// Finish marks the account as finished with an optional error.
+func (r *Result) Finish(err error) {
+	r.FinishedAt = time.Now().UTC()
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// Write encodes the summary as indented JSON.
+func (r *Run) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteFile writes the summary to the file. "-" means stdout.
+func (r *Run) WriteFile(path string) error {
+	if path == "-" {
+		return r.Write(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// ReadRunFile reads the summary written by WriteFile.
+func ReadRunFile(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("decoding run result %s: %w", path, err)
+	}
+
+	return &run, nil
+}
+
+// VacancyIDs returns ids of kept vacancies and vacancies applied to, without duplicates.
+func (r *Result) VacancyIDs() []string {
+	seen := make(map[string]bool, len(r.Kept)+len(r.Applications))
+	ids := make([]string, 0, len(r.Kept)+len(r.Applications))
+
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, application := range r.Applications {
+		if application.Status == ApplicationStatusApplied {
+			add(application.VacancyID)
+		}
+	}
+	for _, vacancy := range r.Kept {
+		add(vacancy.ID)
+	}
+
+	return ids
+}
@@ -1,8 +1,8 @@
 package secrets
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -13,7 +13,8 @@ type Source struct {
 	// Value is an inline secret value provided via configuration or flags.
 	Value string
 	// File points to a file containing the secret value. When set it takes
-	// precedence over Value.
+	// precedence over Value. It may also be a reference with a resolver scheme:
+	// env:NAME, file:/path, exec:command, keyring:name or k8s:key.
 	File string
 }
 
@@ -28,11 +29,16 @@ func Load(src Source) (string, error) {
 
 	file := strings.TrimSpace(src.File)
 	if file != "" {
-		data, err := os.ReadFile(file)
+		scheme, resolve, ref := lookup(file)
+
+		value, err := resolve(context.Background(), ref)
 		if err != nil {
-			return "", fmt.Errorf("reading %s from file %q: %w", name, file, err)
+			if scheme == "file" {
+				return "", fmt.Errorf("reading %s from file %q: %w", name, ref, err)
+			}
+			return "", fmt.Errorf("resolving %s from %s reference %q: %w", name, scheme, ref, err)
 		}
-		src.Value = string(data)
+		src.Value = value
 		src.File = file
 	}
 
@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "token")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	KubernetesDir = dir
+	t.Setenv("HH_TEST_TOKEN", " from-env ")
+	Register("static", func(_ context.Context, ref string) (string, error) {
+		return "static-" + ref, nil
+	})
+
+	tests := []struct {
+		name   string
+		src    Source
+		expect string
+		err    string
+	}{
+		{name: "plain path", src: Source{File: file}, expect: "from-file"},
+		{name: "file scheme", src: Source{File: "file:" + file}, expect: "from-file"},
+		{name: "file takes precedence", src: Source{File: file, Value: "inline"}, expect: "from-file"},
+		{name: "inline value", src: Source{Value: "inline"}, expect: "inline"},
+		{name: "env", src: Source{File: "env:HH_TEST_TOKEN"}, expect: "from-env"},
+		{name: "exec", src: Source{File: "exec:printf 'from-exec\\n'"}, expect: "from-exec"},
+		{name: "k8s relative", src: Source{File: "k8s:token"}, expect: "from-file"},
+		{name: "registered", src: Source{File: "static:value"}, expect: "static-value"},
+		{name: "unknown scheme is a path", src: Source{Name: "token", File: "vault:secret/hh"}, err: `reading token from file "vault:secret/hh"`},
+		{name: "missing env", src: Source{Name: "token", File: "env:HH_TEST_MISSING"}, err: "resolving token from env reference"},
+		{name: "failed exec", src: Source{File: "exec:echo denied >&2; exit 1"}, err: "denied"},
+		{name: "empty", src: Source{Name: "token"}, err: "token is not configured"},
+	}
+
+	for _, tt := range tests {
+		got, err := Load(tt.src)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("%s: expected error %q, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.expect {
+			t.Fatalf("%s: expected %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver returns the secret value referenced by ref. Ref is the part after the scheme.
+type Resolver func(ctx context.Context, ref string) (string, error)
+
+const execTimeout = 30 * time.Second
+
+// KubernetesDir is the directory with a mounted Kubernetes secret used by relative k8s: references.
+var KubernetesDir = "/var/run/secrets/hh-responder"
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]Resolver{
+		"env":     resolveEnv,
+		"file":    resolveFile,
+		"exec":    resolveExec,
+		"keyring": resolveKeyring,
+		"k8s":     resolveKubernetes,
+	}
+)
+
+// Register adds a resolver for the scheme replacing the existing one.
+func Register(scheme string, resolver Resolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+
+	resolvers[scheme] = resolver
+}
+
+// lookup splits a reference into a resolver and its argument. References without a known scheme are file paths.
+func lookup(ref string) (string, Resolver, string) {
+	scheme, rest, ok := strings.Cut(ref, ":")
+	if !ok {
+		return "file", resolveFile, ref
+	}
+
+	resolversMu.RLock()
+	resolver, found := resolvers[scheme]
+	resolversMu.RUnlock()
+
+	if !found {
+		return "file", resolveFile, ref
+	}
+
+	return scheme, resolver, rest
+}
+
+func resolveEnv(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+func resolveFile(_ context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// resolveExec runs the command with sh and returns its output, e.g. exec:pass show hh/token.
+func resolveExec(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	return output(exec.CommandContext(ctx, "sh", "-c", command))
+}
+
+// resolveKeyring reads a user key from the Linux kernel keyring with keyctl. Key ids like %logon:name are passed as is.
+func resolveKeyring(ctx context.Context, key string) (string, error) {
+	if !strings.HasPrefix(key, "%") {
+		key = "%user:" + key
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	return output(exec.CommandContext(ctx, "keyctl", "pipe", key))
+}
+
+// resolveKubernetes reads a key of a secret mounted as a directory. Relative keys are looked up in KubernetesDir.
+func resolveKubernetes(ctx context.Context, key string) (string, error) {
+	if !filepath.IsAbs(key) {
+		key = filepath.Join(KubernetesDir, key)
+	}
+	return resolveFile(ctx, key)
+}
+
+func output(cmd *exec.Cmd) (string, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+
+	return string(out), nil
+}
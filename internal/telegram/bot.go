@@ -0,0 +1,340 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+const (
+	defaultPollTimeout = 30
+	// confirmTimeout limits the request confirming the handled updates when the review ends.
+	confirmTimeout = 5 * time.Second
+	// Polling failures are retried after a pause doubling up to the maximum one.
+	defaultRetryDelay = time.Second
+	maxRetryDelay     = time.Minute
+	// Telegram rejects messages longer than 4096 characters.
+	maxDraftMessageRunes = 1500
+)
+
+// Decision is the user's choice made with an inline button.
+type Decision string
+
+const (
+	DecisionApply   Decision = "apply"
+	DecisionSkip    Decision = "skip"
+	DecisionExclude Decision = "exclude"
+)
+
+// Handler performs the action chosen for the vacancy.
+type Handler func(ctx context.Context, decision Decision, vacancy *headhunter.Vacancy) error
+
+// Bot sends vacancy cards to a chat and waits for decisions made with inline buttons.
+type Bot struct {
+	client      *Client
+	chatID      int64
+	pollTimeout int
+	retryDelay  time.Duration
+	logger      *zap.Logger
+}
+
+type card struct {
+	vacancy   *headhunter.Vacancy
+	messageID int64
+}
+
+func NewBot(client *Client, chatID int64, pollTimeout int, logger *zap.Logger) *Bot {
+	if pollTimeout <= 0 {
+		pollTimeout = defaultPollTimeout
+	}
+
+	return &Bot{
+		client:      client,
+		chatID:      chatID,
+		pollTimeout: pollTimeout,
+		retryDelay:  defaultRetryDelay,
+		logger:      logger,
+	}
+}
+
+// Review sends a card for every vacancy and blocks until each of them gets a decision
+// or the context is canceled. Callbacks from other chats and the ones left from previous
+// reviews are ignored. Temporary polling failures are retried, so the review survives network
+// problems on a server. When a card can't be sent, the cards sent before it are still reviewed
+// and the sending error is returned at the end.
+func (b *Bot) Review(ctx context.Context, vacancies *headhunter.Vacancies, handle Handler) error {
+	offset, err := b.skipStale(ctx)
+	if err != nil {
+		return fmt.Errorf("skip stale updates: %w", err)
+	}
+	// Telegram delivers updates again until a request with the next offset confirms them.
+	defer func() { b.confirm(ctx, offset) }()
+
+	pending := make(map[string]*card, vacancies.Len())
+
+	var sendErr error
+	for i, vacancy := range vacancies.Items {
+		message, err := b.client.SendMessage(ctx, b.chatID, FormatCard(vacancy), keyboard(vacancy.ID))
+		if err != nil {
+			sendErr = fmt.Errorf("send vacancy %s: %w", vacancy.ID, err)
+			b.logger.Warn("sending vacancies to telegram stopped",
+				zap.Error(err),
+				zap.Int("sent", len(pending)),
+				zap.Int("not_sent", vacancies.Len()-i),
+			)
+			break
+		}
+
+		pending[vacancy.ID] = &card{vacancy: vacancy, messageID: message.MessageID}
+	}
+
+	if len(pending) == 0 {
+		return sendErr
+	}
+
+	b.logger.Info("vacancies sent to telegram", zap.Int("count", len(pending)), zap.Int64("chat_id", b.chatID))
+
+	failures := 0
+	for len(pending) > 0 {
+		updates, err := b.client.GetUpdates(ctx, offset, b.pollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !temporary(err) {
+				return fmt.Errorf("get updates: %w", err)
+			}
+
+			failures++
+			if err := b.wait(ctx, err, failures); err != nil {
+				return err
+			}
+			continue
+		}
+		failures = 0
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+
+			if update.CallbackQuery == nil {
+				continue
+			}
+
+			b.handleCallback(ctx, update.CallbackQuery, pending, handle)
+		}
+	}
+
+	b.logger.Info("all vacancies reviewed in telegram")
+
+	return sendErr
+}
+
+// temporary reports polling errors worth retrying. Errors of the Bot API like a revoked token
+// are permanent unless it asks to slow down or fails on its side.
+func temporary(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	return true
+}
+
+// wait pauses after the failed poll: as long as Telegram asks or longer with every failure in a row.
+func (b *Bot) wait(ctx context.Context, err error, failures int) error {
+	delay := min(b.retryDelay<<min(failures-1, 10), maxRetryDelay)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		delay = apiErr.RetryAfter
+	}
+
+	b.logger.Warn("getting telegram updates failed, retrying",
+		zap.Error(err),
+		zap.Int("failures", failures),
+		zap.Duration("delay", delay),
+	)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// skipStale returns the offset after updates sent before the review, e.g. presses of buttons
+// of cards from previous runs. The offset -1 asks for the last update only.
+func (b *Bot) skipStale(ctx context.Context) (int64, error) {
+	updates, err := b.client.GetUpdates(ctx, -1, 0)
+	if err != nil || len(updates) == 0 {
+		return 0, err
+	}
+
+	last := updates[len(updates)-1].UpdateID
+	b.logger.Debug("skipping stale telegram updates", zap.Int64("last_update_id", last))
+
+	return last + 1, nil
+}
+
+// confirm marks updates before the offset as handled, so they are not delivered again.
+func (b *Bot) confirm(ctx context.Context, offset int64) {
+	if offset == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), confirmTimeout)
+	defer cancel()
+
+	if _, err := b.client.GetUpdates(ctx, offset, 0); err != nil {
+		b.logger.Warn("confirming handled telegram updates", zap.Int64("offset", offset), zap.Error(err))
+	}
+}
+
+// handleCallback performs the decision. Failures of answers and edits are logged only: the decision is made already.
+func (b *Bot) handleCallback(ctx context.Context, query *CallbackQuery, pending map[string]*card, handle Handler) {
+	if query.Message == nil || query.Message.Chat.ID != b.chatID {
+		b.logger.Warn("ignoring telegram callback from unknown chat", zap.Int64("user_id", query.From.ID))
+		return
+	}
+
+	decision, vacancyID, ok := parseCallbackData(query.Data)
+	if !ok {
+		b.answer(ctx, query.ID, "Unknown action")
+		return
+	}
+
+	c, ok := pending[vacancyID]
+	if !ok {
+		b.answer(ctx, query.ID, "Vacancy is already handled")
+		return
+	}
+
+	if err := handle(ctx, decision, c.vacancy); err != nil {
+		b.logger.Warn("telegram action failed",
+			zap.String("vacancy_id", vacancyID),
+			zap.String("decision", string(decision)),
+			zap.Error(err),
+		)
+		b.answer(ctx, query.ID, fmt.Sprintf("Failed: %s", err))
+		return
+	}
+
+	delete(pending, vacancyID)
+
+	b.logger.Info("vacancy reviewed in telegram",
+		zap.String("vacancy_id", vacancyID),
+		zap.String("decision", string(decision)),
+		zap.Int("pending", len(pending)),
+	)
+
+	b.answer(ctx, query.ID, decisionLabel(decision))
+
+	text := fmt.Sprintf("%s\n\n<b>%s</b>", FormatCard(c.vacancy), decisionLabel(decision))
+	if err := b.client.EditMessageText(ctx, b.chatID, c.messageID, text); err != nil {
+		b.logger.Warn("updating telegram card", zap.String("vacancy_id", vacancyID), zap.Error(err))
+	}
+}
+
+func (b *Bot) answer(ctx context.Context, queryID, text string) {
+	if err := b.client.AnswerCallbackQuery(ctx, queryID, text); err != nil {
+		b.logger.Warn("answering telegram callback", zap.String("text", text), zap.Error(err))
+	}
+}
+
+// FormatCard renders the vacancy as an HTML message.
+func FormatCard(vacancy *headhunter.Vacancy) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "<b>%s</b>\n", html.EscapeString(vacancy.Name))
+	fmt.Fprintf(&builder, "%s", html.EscapeString(vacancy.Employer.Name))
+	if vacancy.Area.Name != "" {
+		fmt.Fprintf(&builder, " · %s", html.EscapeString(vacancy.Area.Name))
+	}
+	builder.WriteString("\n")
+
+	if salary := vacancy.SalaryString(); salary != "" {
+		fmt.Fprintf(&builder, "Salary: %s\n", html.EscapeString(salary))
+	}
+
+	if ai := vacancy.AI; ai != nil {
+		switch {
+		case ai.Error != "":
+			fmt.Fprintf(&builder, "AI error: %s\n", html.EscapeString(ai.Error))
+		default:
+			fmt.Fprintf(&builder, "AI score: %s", strconv.FormatFloat(ai.Score, 'f', 2, 64))
+			if ai.Reason != "" {
+				fmt.Fprintf(&builder, " — %s", html.EscapeString(ai.Reason))
+			}
+			builder.WriteString("\n")
+		}
+
+		if ai.ResumeTitle != "" {
+			fmt.Fprintf(&builder, "Resume: %s\n", html.EscapeString(ai.ResumeTitle))
+		}
+
+		if ai.Message != "" {
+			message := []rune(ai.Message)
+			if len(message) > maxDraftMessageRunes {
+				message = append(message[:maxDraftMessageRunes], '…')
+			}
+			fmt.Fprintf(&builder, "\nDraft message:\n<i>%s</i>\n", html.EscapeString(string(message)))
+		}
+	}
+
+	if vacancy.AlternateURL != "" {
+		fmt.Fprintf(&builder, "\n%s", html.EscapeString(vacancy.AlternateURL))
+	}
+
+	return strings.TrimSpace(builder.String())
+}
+
+func keyboard(vacancyID string) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: "Apply", CallbackData: callbackData(DecisionApply, vacancyID)},
+			{Text: "Skip", CallbackData: callbackData(DecisionSkip, vacancyID)},
+			{Text: "Exclude", CallbackData: callbackData(DecisionExclude, vacancyID)},
+		}},
+	}
+}
+
+func callbackData(decision Decision, vacancyID string) string {
+	return fmt.Sprintf("%s:%s", decision, vacancyID)
+}
+
+func parseCallbackData(data string) (Decision, string, bool) {
+	action, vacancyID, found := strings.Cut(data, ":")
+	if !found || vacancyID == "" {
+		return "", "", false
+	}
+
+	decision := Decision(action)
+	switch decision {
+	case DecisionApply, DecisionSkip, DecisionExclude:
+		return decision, vacancyID, true
+	default:
+		return "", "", false
+	}
+}
+
+func decisionLabel(decision Decision) string {
+	switch decision {
+	case DecisionApply:
+		return "Applied"
+	case DecisionExclude:
+		return "Excluded"
+	default:
+		return "Skipped"
+	}
+}
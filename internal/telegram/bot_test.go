@@ -0,0 +1,299 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+type fakeAPI struct {
+	mu sync.Mutex
+	// stale are updates left from previous reviews.
+	stale    []Update
+	updates  [][]Update
+	offsets  []int64
+	sent     []map[string]any
+	edited   []map[string]any
+	answered []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var payload map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+
+	var result any = true
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+		f.sent = append(f.sent, payload)
+		result = Message{MessageID: int64(len(f.sent)), Chat: Chat{ID: 42}}
+	case strings.HasSuffix(r.URL.Path, "/editMessageText"):
+		f.edited = append(f.edited, payload)
+	case strings.HasSuffix(r.URL.Path, "/answerCallbackQuery"):
+		f.answered = append(f.answered, payload["text"].(string))
+	case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+		offset := int64(payload["offset"].(float64))
+		f.offsets = append(f.offsets, offset)
+
+		var batch []Update
+		switch {
+		case offset == -1:
+			batch = f.stale
+		case len(f.updates) > 0:
+			batch, f.updates = f.updates[0], f.updates[1:]
+		}
+		result = batch
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+func callback(updateID, chatID int64, data string) Update {
+	return Update{
+		UpdateID: updateID,
+		CallbackQuery: &CallbackQuery{
+			ID:      "q",
+			Message: &Message{Chat: Chat{ID: chatID}},
+			Data:    data,
+		},
+	}
+}
+
+func TestBotReview(t *testing.T) {
+	api := &fakeAPI{
+		stale: []Update{callback(0, 42, "skip:2")},
+		updates: [][]Update{
+			{callback(1, 100500, "apply:1")},
+			{callback(2, 42, "apply:1"), callback(3, 42, "unknown")},
+			{callback(4, 42, "exclude:2")},
+		},
+	}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client := New("secret", zap.NewNop())
+	client.APIURL = server.URL
+
+	vacancies := &headhunter.Vacancies{Items: []*headhunter.Vacancy{
+		{ID: "1", Name: "Go <Developer>"},
+		{ID: "2", Name: "SRE"},
+	}}
+
+	decisions := make(map[string]Decision)
+	handler := func(_ context.Context, decision Decision, vacancy *headhunter.Vacancy) error {
+		decisions[vacancy.ID] = decision
+		return nil
+	}
+
+	bot := NewBot(client, 42, 1, zap.NewNop())
+	if err := bot.Review(context.Background(), vacancies, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decisions["1"] != DecisionApply || decisions["2"] != DecisionExclude {
+		t.Fatalf("unexpected decisions: %v", decisions)
+	}
+
+	if len(api.sent) != 2 {
+		t.Fatalf("expected 2 cards, got %d", len(api.sent))
+	}
+
+	if !strings.Contains(api.sent[0]["text"].(string), "Go &lt;Developer&gt;") {
+		t.Fatalf("expected escaped vacancy name, got %q", api.sent[0]["text"])
+	}
+
+	if len(api.edited) != 2 {
+		t.Fatalf("expected 2 edited cards, got %d", len(api.edited))
+	}
+
+	if api.answered[0] != "Applied" || api.answered[1] != "Unknown action" {
+		t.Fatalf("unexpected answers: %v", api.answered)
+	}
+
+	// Stale updates are skipped and the handled ones are confirmed at the end.
+	expected := []int64{-1, 1, 2, 4, 5}
+	if len(api.offsets) != len(expected) {
+		t.Fatalf("unexpected offsets: %v", api.offsets)
+	}
+	for i := range expected {
+		if api.offsets[i] != expected[i] {
+			t.Fatalf("unexpected offsets: %v", api.offsets)
+		}
+	}
+}
+
+func TestBotReviewKeepsPollingOnAnswerErrors(t *testing.T) {
+	api := &fakeAPI{updates: [][]Update{
+		{callback(1, 42, "apply:1")},
+		{callback(2, 42, "skip:2")},
+	}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/answerCallbackQuery") || strings.HasSuffix(r.URL.Path, "/editMessageText") {
+			http.Error(w, `{"ok": false, "description": "Bad Gateway"}`, http.StatusBadGateway)
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := New("secret", zap.NewNop())
+	client.APIURL = server.URL
+
+	vacancies := &headhunter.Vacancies{Items: []*headhunter.Vacancy{{ID: "1"}, {ID: "2"}}}
+
+	var reviewed []string
+	handler := func(_ context.Context, _ Decision, vacancy *headhunter.Vacancy) error {
+		reviewed = append(reviewed, vacancy.ID)
+		return nil
+	}
+
+	if err := NewBot(client, 42, 1, zap.NewNop()).Review(context.Background(), vacancies, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reviewed) != 2 {
+		t.Fatalf("expected both vacancies reviewed, got %v", reviewed)
+	}
+}
+
+func TestBotReviewRetriesPolling(t *testing.T) {
+	api := &fakeAPI{updates: [][]Update{{callback(1, 42, "skip:1")}}}
+
+	var mu sync.Mutex
+	failures := []string{
+		`{"ok": false, "error_code": 502, "description": "Bad Gateway"}`,
+		`{"ok": false, "error_code": 429, "description": "Too Many Requests"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var payload map[string]any
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if strings.HasSuffix(r.URL.Path, "/getUpdates") && payload["offset"].(float64) != -1 && len(failures) > 0 {
+			_, _ = w.Write([]byte(failures[0]))
+			failures = failures[1:]
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := New("secret", zap.NewNop())
+	client.APIURL = server.URL
+
+	bot := NewBot(client, 42, 1, zap.NewNop())
+	bot.retryDelay = time.Millisecond
+
+	vacancies := &headhunter.Vacancies{Items: []*headhunter.Vacancy{{ID: "1"}}}
+	handler := func(context.Context, Decision, *headhunter.Vacancy) error { return nil }
+
+	if err := bot.Review(context.Background(), vacancies, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(failures) != 0 {
+		t.Fatalf("expected all failures to be retried, left %d", len(failures))
+	}
+}
+
+func TestBotReviewStopsOnPermanentErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": Message{MessageID: 1}})
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": false, "error_code": 401, "description": "Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	client := New("secret", zap.NewNop())
+	client.APIURL = server.URL
+
+	bot := NewBot(client, 42, 1, zap.NewNop())
+	bot.retryDelay = time.Millisecond
+
+	vacancies := &headhunter.Vacancies{Items: []*headhunter.Vacancy{{ID: "1"}}}
+	handler := func(context.Context, Decision, *headhunter.Vacancy) error { return nil }
+
+	var apiErr *APIError
+	if err := bot.Review(context.Background(), vacancies, handler); !errors.As(err, &apiErr) || apiErr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+}
+
+func TestBotReviewHandlesSentCardsWhenSendingFails(t *testing.T) {
+	api := &fakeAPI{updates: [][]Update{{callback(1, 42, "apply:1")}}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") && len(api.sent) > 0 {
+			_, _ = w.Write([]byte(`{"ok": false, "error_code": 400, "description": "Bad Request"}`))
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := New("secret", zap.NewNop())
+	client.APIURL = server.URL
+
+	vacancies := &headhunter.Vacancies{Items: []*headhunter.Vacancy{{ID: "1"}, {ID: "2"}}}
+
+	var reviewed []string
+	handler := func(_ context.Context, _ Decision, vacancy *headhunter.Vacancy) error {
+		reviewed = append(reviewed, vacancy.ID)
+		return nil
+	}
+
+	err := NewBot(client, 42, 1, zap.NewNop()).Review(context.Background(), vacancies, handler)
+	if err == nil || !strings.Contains(err.Error(), "send vacancy 2") {
+		t.Fatalf("expected the sending error, got %v", err)
+	}
+
+	if len(reviewed) != 1 || reviewed[0] != "1" {
+		t.Fatalf("expected the sent card reviewed, got %v", reviewed)
+	}
+}
+
+func TestParseCallbackData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data     string
+		decision Decision
+		id       string
+		ok       bool
+	}{
+		{data: "apply:123", decision: DecisionApply, id: "123", ok: true},
+		{data: "skip:1", decision: DecisionSkip, id: "1", ok: true},
+		{data: "exclude:9", decision: DecisionExclude, id: "9", ok: true},
+		{data: "delete:9"},
+		{data: "apply:"},
+		{data: "apply"},
+	}
+
+	for _, tt := range tests {
+		decision, id, ok := parseCallbackData(tt.data)
+		if decision != tt.decision || id != tt.id || ok != tt.ok {
+			t.Fatalf("%q: got (%q, %q, %v)", tt.data, decision, id, ok)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	apiURL = "https://api.telegram.org"
+	// Telegram keeps long polling requests open up to the requested timeout.
+	// The HTTP timeout must be greater than the maximum poll timeout.
+	maxPollTimeout = 50
+	httpTimeout    = 60 * time.Second
+)
+
+// Client is a minimal Telegram Bot API client.
+type Client struct {
+	token      string
+	logger     *zap.Logger
+	HTTPClient *http.Client
+	APIURL     string
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// APIError is an error answered by the Bot API.
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	// RetryAfter is the pause Telegram asks for when requests are too frequent.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %s", e.Method, e.Description)
+}
+
+// Temporary reports errors worth retrying: flood control and failures on the Telegram side.
+func (e *APIError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+}
+
+func New(token string, logger *zap.Logger) *Client {
+	return &Client{
+		token:  token,
+		logger: logger,
+		APIURL: apiURL,
+		HTTPClient: &http.Client{
+			Timeout: httpTimeout,
+		},
+	}
+}
+
+// SendMessage sends an HTML formatted message with an optional inline keyboard.
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup) (*Message, error) {
+	payload := map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+	if markup != nil {
+		payload["reply_markup"] = markup
+	}
+
+	var message Message
+	if err := c.call(ctx, "sendMessage", payload, &message); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// EditMessageText replaces the text of the message and drops its inline keyboard.
+func (c *Client) EditMessageText(ctx context.Context, chatID, messageID int64, text string) error {
+	payload := map[string]any{
+		"chat_id":                  chatID,
+		"message_id":               messageID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+
+	return c.call(ctx, "editMessageText", payload, nil)
+}
+
+// AnswerCallbackQuery acknowledges the button press and shows a short notification.
+func (c *Client) AnswerCallbackQuery(ctx context.Context, id, text string) error {
+	payload := map[string]any{
+		"callback_query_id": id,
+		"text":              text,
+	}
+
+	return c.call(ctx, "answerCallbackQuery", payload, nil)
+}
+
+// GetUpdates long-polls the Bot API for callback queries starting from the offset.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error) {
+	if timeout > maxPollTimeout {
+		timeout = maxPollTimeout
+	}
+
+	payload := map[string]any{
+		"offset":          offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"callback_query"},
+	}
+
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", payload, &updates); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, payload any, target any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", method, err)
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.APIURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Do not log the url since it contains the bot token.
+	c.logger.Debug("make telegram request", zap.String("method", method))
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram %s: %w", method, redact(err, c.token))
+	}
+	defer resp.Body.Close()
+
+	var response apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("telegram %s: decode response (status %s): %w", method, resp.Status, err)
+	}
+
+	if !response.OK {
+		code := response.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+		return &APIError{
+			Method:      method,
+			Code:        code,
+			Description: response.Description,
+			RetryAfter:  time.Duration(response.Parameters.RetryAfter) * time.Second,
+		}
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(response.Result, target); err != nil {
+		return fmt.Errorf("telegram %s: decode result: %w", method, err)
+	}
+
+	return nil
+}
+
+// redact hides the bot token which is a part of request URL in transport errors.
+func redact(err error, token string) error {
+	var urlErr *url.Error
+	if token == "" || !errors.As(err, &urlErr) {
+		return err
+	}
+
+	urlErr.URL = strings.ReplaceAll(urlErr.URL, token, "<redacted>")
+
+	return urlErr
+}
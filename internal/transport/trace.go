@@ -0,0 +1,186 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spigell/hh-responder/internal/httpbody"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// maxTraceBody limits bodies kept for the trace. Responses are passed on in full, their consumer limits them.
+	maxTraceBody = 1 << 20
+)
+
+// sensitiveHeaders are replaced in traces.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Tokens and secrets in JSON and form bodies, e.g. of the OAuth token exchange.
+var (
+	// A value cut by the trace limit is redacted up to the end.
+	sensitiveJSON = regexp.MustCompile(`("(?:access_token|refresh_token|client_secret|code)"\s*:\s*")[^"]*("|$)`)
+	sensitiveForm = regexp.MustCompile(`(^|&)((?:access_token|refresh_token|client_secret|code)=)[^&]*`)
+)
+
+// Tracer dumps every request and response to the writer with credentials redacted.
+type Tracer struct {
+	next http.RoundTripper
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+// NewTracer wraps the transport. A nil transport means http.DefaultTransport.
+func NewTracer(next http.RoundTripper, w io.Writer) *Tracer {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Tracer{next: next, w: w}
+}
+
+func (t *Tracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var b strings.Builder
+	started := time.Now()
+
+	fmt.Fprintf(&b, "--- %s request\n%s %s\n", started.Format(time.RFC3339), req.Method, redactQuery(req.URL.String()))
+	writeHeaders(&b, req.Header)
+
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, maxTraceBody+1))
+			body.Close()
+			writeBody(&b, truncate(data, len(data) > maxTraceBody))
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		fmt.Fprintf(&b, "--- error after %s\n%s\n\n", time.Since(started).Round(time.Millisecond), err)
+		t.write(b.String())
+		return nil, err
+	}
+
+	fmt.Fprintf(&b, "--- response after %s\n%s %s\n", time.Since(started).Round(time.Millisecond), resp.Proto, resp.Status)
+	writeHeaders(&b, resp.Header)
+
+	// Only the traced part is buffered. The consumer reads it and then the rest of the body.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTraceBody+1))
+	truncated := len(data) > maxTraceBody
+	rest := resp.Body
+	if err != nil {
+		fmt.Fprintf(&b, "reading body: %s\n", err)
+		rest = &failedBody{err: err, Closer: resp.Body}
+	}
+	resp.Body = &tracedBody{Reader: io.MultiReader(bytes.NewReader(data), rest), Closer: resp.Body}
+
+	writeBody(&b, decodeForTrace(resp.Header.Get("Content-Encoding"), data, truncated))
+	b.WriteString("\n")
+	t.write(b.String())
+
+	return resp, nil
+}
+
+// tracedBody passes on the traced part of the body followed by the unread rest.
+type tracedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// failedBody repeats the error reading the body to its consumer.
+type failedBody struct {
+	err error
+	io.Closer
+}
+
+func (b *failedBody) Read([]byte) (int, error) {
+	return 0, b.err
+}
+
+// write keeps the dump of a request in one piece when requests run concurrently.
+func (t *Tracer) write(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	io.WriteString(t.w, s)
+}
+
+func writeHeaders(b *strings.Builder, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			if slices.Contains(sensitiveHeaders, http.CanonicalHeaderKey(key)) {
+				value = redacted
+			}
+			fmt.Fprintf(b, "%s: %s\n", key, value)
+		}
+	}
+}
+
+func writeBody(b *strings.Builder, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	b.WriteString("\n")
+	b.WriteString(redactBody(string(data)))
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		b.WriteString("\n")
+	}
+}
+
+// decodeForTrace returns the readable body. The response itself is passed on encoded.
+// A truncated body is decoded as far as possible.
+func decodeForTrace(encoding string, data []byte, truncated bool) []byte {
+	if len(data) == 0 {
+		return data
+	}
+
+	raw := data[:min(len(data), maxTraceBody)]
+	body, err := httpbody.Decode(encoding, bytes.NewReader(raw))
+	if err != nil {
+		return []byte(fmt.Sprintf("[%d bytes in %s: %s]", len(raw), encoding, err))
+	}
+	defer body.Close()
+
+	decoded, err := io.ReadAll(io.LimitReader(body, maxTraceBody+1))
+	if err != nil && !truncated {
+		return []byte(fmt.Sprintf("[%d bytes of broken %s: %s]", len(raw), encoding, err))
+	}
+
+	return truncate(decoded, truncated || len(decoded) > maxTraceBody)
+}
+
+// truncate cuts the body to the trace limit and marks it.
+func truncate(data []byte, truncated bool) []byte {
+	if !truncated {
+		return data
+	}
+
+	data = data[:min(len(data), maxTraceBody)]
+	return append(data[:len(data):len(data)], fmt.Sprintf("\n[truncated to %d bytes]", maxTraceBody)...)
+}
+
+func redactBody(body string) string {
+	body = sensitiveJSON.ReplaceAllString(body, "${1}"+redacted+"${2}")
+	return sensitiveForm.ReplaceAllString(body, "${1}${2}"+redacted)
+}
+
+func redactQuery(rawURL string) string {
+	before, query, ok := strings.Cut(rawURL, "?")
+	if !ok {
+		return rawURL
+	}
+	return before + "?" + sensitiveForm.ReplaceAllString(query, "${1}${2}"+redacted)
+}
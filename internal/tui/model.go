@@ -0,0 +1,619 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// SortMode defines the order of vacancies in the list pane.
+type SortMode int
+
+const (
+	SortDefault SortMode = iota
+	SortByScore
+	SortBySalary
+)
+
+func (s SortMode) String() string {
+	switch s {
+	case SortByScore:
+		return "ai score"
+	case SortBySalary:
+		return "salary"
+	default:
+		return "default"
+	}
+}
+
+// Actions are callbacks performing the real work for the chosen vacancies.
+// Apply and Exclude are never called concurrently.
+type Actions struct {
+	Apply   func(vacancy *headhunter.Vacancy, message string) error
+	Exclude func(vacancy *headhunter.Vacancy, reason string) error
+	// Details is optional. It loads the full vacancy when the search result lacks a description.
+	Details func(vacancy *headhunter.Vacancy) (*headhunter.Vacancy, error)
+}
+
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeEditMessage
+	modeExcludeReason
+)
+
+const (
+	actionApply   = "applied"
+	actionExclude = "excluded"
+)
+
+type item struct {
+	index    int
+	vacancy  *headhunter.Vacancy
+	message  string
+	selected bool
+	busy     bool
+	detailed bool
+}
+
+type actionResult struct {
+	item   *item
+	action string
+	err    error
+}
+
+type actionDoneMsg []actionResult
+
+type detailsMsg struct {
+	item    *item
+	vacancy *headhunter.Vacancy
+	err     error
+}
+
+// Result lists vacancies handled during the review.
+type Result struct {
+	Applied  []string
+	Excluded []string
+}
+
+// Model is the Bubble Tea model of the review screen.
+type Model struct {
+	items   []*item
+	cursor  int
+	sort    SortMode
+	mode    mode
+	actions Actions
+	status  string
+
+	// actionMu runs actions one at a time: they share the state of the account.
+	actionMu sync.Mutex
+	// quitting is set when the user quits while actions are still running.
+	quitting bool
+	result   Result
+
+	width  int
+	height int
+
+	detail  viewport.Model
+	editor  textarea.Model
+	reasons textinput.Model
+}
+
+// NewModel creates the review screen for vacancies. Draft messages are taken from
+// AI assessments and fall back to the default message.
+func NewModel(vacancies *headhunter.Vacancies, defaultMessage string, actions Actions) *Model {
+	items := make([]*item, 0, vacancies.Len())
+	for _, vacancy := range vacancies.Items {
+		message := defaultMessage
+		if vacancy.AI != nil && vacancy.AI.Message != "" {
+			message = vacancy.AI.Message
+		}
+
+		items = append(items, &item{
+			index:    len(items),
+			vacancy:  vacancy,
+			message:  message,
+			detailed: vacancy.Description != "",
+		})
+	}
+
+	editor := textarea.New()
+	editor.Placeholder = "Cover letter"
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 0
+
+	reasons := textinput.New()
+	reasons.Placeholder = "Reason"
+	reasons.Prompt = "Exclude reason: "
+
+	m := &Model{
+		items:   items,
+		actions: actions,
+		detail:  viewport.New(0, 0),
+		editor:  editor,
+		reasons: reasons,
+		width:   120,
+		height:  40,
+	}
+	m.resize()
+
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.loadDetails()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+	case actionDoneMsg:
+		return m, m.handleActionDone(msg)
+	case detailsMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("loading vacancy %s failed: %s", msg.item.vacancy.ID, msg.err)
+			return m, nil
+		}
+		// Keep the AI assessment made for the short version.
+		msg.vacancy.AI = msg.item.vacancy.AI
+		msg.item.vacancy = msg.vacancy
+		return m, nil
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeEditMessage:
+			return m, m.updateEditor(msg)
+		case modeExcludeReason:
+			return m, m.updateReason(msg)
+		default:
+			return m, m.updateBrowse(msg)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) updateBrowse(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		// Results of running actions would be lost otherwise.
+		if m.busy() {
+			m.quitting = true
+			m.status = "waiting for running actions to finish..."
+			return nil
+		}
+		return tea.Quit
+	case "up", "k":
+		m.moveCursor(-1)
+		return m.loadDetails()
+	case "down", "j":
+		m.moveCursor(1)
+		return m.loadDetails()
+	case "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.detail, cmd = m.detail.Update(msg)
+		return cmd
+	case " ":
+		if current := m.current(); current != nil {
+			current.selected = !current.selected
+		}
+	case "s":
+		m.cycleSort()
+	case "a":
+		return m.applySelected()
+	case "e":
+		if current := m.current(); current != nil {
+			m.mode = modeEditMessage
+			m.editor.SetValue(current.message)
+			return m.editor.Focus()
+		}
+	case "x":
+		if current := m.current(); current != nil {
+			m.mode = modeExcludeReason
+			m.reasons.SetValue("")
+			return m.reasons.Focus()
+		}
+	}
+
+	return nil
+}
+
+func (m *Model) updateEditor(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeBrowse
+		m.editor.Blur()
+		m.status = "message editing canceled"
+		return nil
+	case "ctrl+s":
+		if current := m.current(); current != nil {
+			current.message = strings.TrimSpace(m.editor.Value())
+		}
+		m.mode = modeBrowse
+		m.editor.Blur()
+		m.status = "message saved"
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return cmd
+}
+
+func (m *Model) updateReason(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeBrowse
+		m.reasons.Blur()
+		return nil
+	case "enter":
+		m.mode = modeBrowse
+		m.reasons.Blur()
+
+		current := m.current()
+		if current == nil || current.busy {
+			return nil
+		}
+
+		reason := strings.TrimSpace(m.reasons.Value())
+		if reason == "" {
+			reason = headhunter.ExcludeReasonManualApply
+		}
+
+		current.busy = true
+		vacancy := current.vacancy
+		return func() tea.Msg {
+			m.actionMu.Lock()
+			defer m.actionMu.Unlock()
+
+			return actionDoneMsg{{item: current, action: actionExclude, err: m.actions.Exclude(vacancy, reason)}}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.reasons, cmd = m.reasons.Update(msg)
+	return cmd
+}
+
+// applySelected applies to all selected vacancies or to the current one if nothing is selected.
+func (m *Model) applySelected() tea.Cmd {
+	targets := make([]*item, 0)
+	for _, it := range m.items {
+		if it.selected && !it.busy {
+			targets = append(targets, it)
+		}
+	}
+
+	if len(targets) == 0 {
+		if current := m.current(); current != nil && !current.busy {
+			targets = append(targets, current)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	type job struct {
+		item    *item
+		vacancy *headhunter.Vacancy
+		message string
+	}
+
+	jobs := make([]job, 0, len(targets))
+	for _, it := range targets {
+		it.busy = true
+		jobs = append(jobs, job{item: it, vacancy: it.vacancy, message: it.message})
+	}
+
+	m.status = fmt.Sprintf("applying to %d vacancies...", len(jobs))
+
+	// Applications are sent one by one to be gentle with the API.
+	return func() tea.Msg {
+		m.actionMu.Lock()
+		defer m.actionMu.Unlock()
+
+		results := make(actionDoneMsg, 0, len(jobs))
+		for _, j := range jobs {
+			results = append(results, actionResult{item: j.item, action: actionApply, err: m.actions.Apply(j.vacancy, j.message)})
+		}
+		return results
+	}
+}
+
+func (m *Model) handleActionDone(msg actionDoneMsg) tea.Cmd {
+	failures := make([]string, 0)
+	done := 0
+
+	for _, result := range msg {
+		result.item.busy = false
+
+		if result.err != nil {
+			failures = append(failures, fmt.Sprintf("vacancy %s: %s", result.item.vacancy.ID, result.err))
+			continue
+		}
+
+		done++
+		m.status = fmt.Sprintf("vacancy %s %s", result.item.vacancy.ID, result.action)
+
+		if result.action == actionApply {
+			m.result.Applied = append(m.result.Applied, result.item.vacancy.ID)
+		} else {
+			m.result.Excluded = append(m.result.Excluded, result.item.vacancy.ID)
+		}
+
+		for idx, it := range m.items {
+			if it == result.item {
+				m.items = append(m.items[:idx], m.items[idx+1:]...)
+				break
+			}
+		}
+	}
+
+	if len(msg) > 1 {
+		m.status = fmt.Sprintf("%d of %d vacancies %s", done, len(msg), msg[0].action)
+	}
+	if len(failures) > 0 {
+		m.status = strings.Join(failures, "; ")
+	}
+
+	if m.cursor >= len(m.items) && m.cursor > 0 {
+		m.cursor = len(m.items) - 1
+	}
+
+	if m.quitting && !m.busy() {
+		return tea.Quit
+	}
+
+	return m.loadDetails()
+}
+
+// Result returns vacancies applied to and excluded so far.
+func (m *Model) Result() Result {
+	return m.result
+}
+
+func (m *Model) busy() bool {
+	for _, it := range m.items {
+		if it.busy {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Model) loadDetails() tea.Cmd {
+	current := m.current()
+	if current == nil || current.detailed || m.actions.Details == nil {
+		return nil
+	}
+
+	current.detailed = true
+	vacancy := current.vacancy
+	return func() tea.Msg {
+		full, err := m.actions.Details(vacancy)
+		return detailsMsg{item: current, vacancy: full, err: err}
+	}
+}
+
+func (m *Model) current() *item {
+	if len(m.items) == 0 {
+		return nil
+	}
+	return m.items[m.cursor]
+}
+
+func (m *Model) moveCursor(delta int) {
+	m.cursor += delta
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.cursor > len(m.items)-1 {
+		m.cursor = max(len(m.items)-1, 0)
+	}
+	m.detail.GotoTop()
+}
+
+func (m *Model) cycleSort() {
+	current := m.current()
+
+	m.sort = (m.sort + 1) % 3
+	switch m.sort {
+	case SortByScore:
+		sort.SliceStable(m.items, func(i, j int) bool { return score(m.items[i]) > score(m.items[j]) })
+	case SortBySalary:
+		sort.SliceStable(m.items, func(i, j int) bool { return salary(m.items[i]) > salary(m.items[j]) })
+	default:
+		sort.SliceStable(m.items, func(i, j int) bool { return m.items[i].index < m.items[j].index })
+	}
+
+	for idx, it := range m.items {
+		if it == current {
+			m.cursor = idx
+		}
+	}
+
+	m.status = fmt.Sprintf("sorted by %s", m.sort)
+}
+
+func score(it *item) float64 {
+	if it.vacancy.AI == nil || it.vacancy.AI.Error != "" {
+		return -1
+	}
+	return it.vacancy.AI.Score
+}
+
+func salary(it *item) int {
+	return max(it.vacancy.Salary.From, it.vacancy.Salary.To)
+}
+
+func (m *Model) resize() {
+	listWidth, detailWidth, paneHeight := m.layout()
+
+	m.detail.Width = detailWidth
+	m.detail.Height = paneHeight
+	m.editor.SetWidth(detailWidth)
+	m.editor.SetHeight(paneHeight)
+	m.reasons.Width = listWidth + detailWidth
+}
+
+func (m *Model) layout() (int, int, int) {
+	listWidth := m.width * 2 / 5
+	detailWidth := m.width - listWidth - 4
+	paneHeight := m.height - 4
+
+	return max(listWidth, 10), max(detailWidth, 10), max(paneHeight, 3)
+}
+
+var (
+	paneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	cursorStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	busyStyle     = lipgloss.NewStyle().Faint(true)
+	headerStyle   = lipgloss.NewStyle().Bold(true)
+	footerStyle   = lipgloss.NewStyle().Faint(true)
+	helpBrowse    = "↑/↓ move • space select • a apply • e edit message • x exclude • s sort • pgup/pgdn scroll • q quit"
+	helpEditor    = "ctrl+s save • esc cancel"
+	helpExclusion = "enter exclude • esc cancel"
+)
+
+func (m *Model) View() string {
+	listWidth, detailWidth, paneHeight := m.layout()
+
+	list := paneStyle.Width(listWidth).Height(paneHeight).Render(m.renderList(listWidth-2, paneHeight))
+
+	var right string
+	if m.mode == modeEditMessage {
+		right = m.editor.View()
+	} else {
+		m.detail.SetContent(lipgloss.NewStyle().Width(detailWidth - 2).Render(m.renderDetails()))
+		right = m.detail.View()
+	}
+	details := paneStyle.Width(detailWidth).Height(paneHeight).Render(right)
+
+	help := helpBrowse
+	switch m.mode {
+	case modeEditMessage:
+		help = helpEditor
+	case modeExcludeReason:
+		help = m.reasons.View() + "  " + helpExclusion
+	}
+
+	footer := footerStyle.Render(fmt.Sprintf("%d vacancies • sort: %s • %s", len(m.items), m.sort, m.status))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, list, details),
+		footer,
+		help,
+	)
+}
+
+func (m *Model) renderList(width, height int) string {
+	if len(m.items) == 0 {
+		return "No vacancies left. Press q to quit."
+	}
+
+	// Keep the cursor visible by scrolling the window of rendered lines.
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	end := min(start+height, len(m.items))
+
+	lines := make([]string, 0, end-start)
+	for idx := start; idx < end; idx++ {
+		it := m.items[idx]
+
+		mark := "[ ]"
+		if it.selected {
+			mark = "[x]"
+		}
+
+		scoreLabel := " -- "
+		if s := score(it); s >= 0 {
+			scoreLabel = strconv.FormatFloat(s, 'f', 2, 64)
+		}
+
+		line := truncate(fmt.Sprintf("%s %s %s / %s", mark, scoreLabel, it.vacancy.Name, it.vacancy.Employer.Name), width)
+		switch {
+		case idx == m.cursor:
+			line = cursorStyle.Render(line)
+		case it.busy:
+			line = busyStyle.Render(line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderDetails() string {
+	current := m.current()
+	if current == nil {
+		return ""
+	}
+
+	v := current.vacancy
+
+	var builder strings.Builder
+	builder.WriteString(headerStyle.Render(v.Name) + "\n")
+	fmt.Fprintf(&builder, "%s · %s\n", v.Employer.Name, v.Area.Name)
+
+	if salary := v.SalaryString(); salary != "" {
+		fmt.Fprintf(&builder, "Salary: %s\n", salary)
+	}
+	for _, field := range [][2]string{
+		{"Schedule", v.Schedule.Name},
+		{"Experience", v.Experience.Name},
+		{"Employment", v.Employment.Name},
+	} {
+		if field[1] != "" {
+			fmt.Fprintf(&builder, "%s: %s\n", field[0], field[1])
+		}
+	}
+	builder.WriteString(v.AlternateURL + "\n")
+
+	if skills := v.KeySkillNames(); len(skills) > 0 {
+		fmt.Fprintf(&builder, "\n%s\n%s\n", headerStyle.Render("Key skills"), strings.Join(skills, ", "))
+	}
+
+	if ai := v.AI; ai != nil {
+		builder.WriteString("\n" + headerStyle.Render("AI assessment") + "\n")
+		if ai.Error != "" {
+			fmt.Fprintf(&builder, "Error: %s\n", ai.Error)
+		} else {
+			fmt.Fprintf(&builder, "Fit: %t, score: %.2f\n%s\n", ai.Fit, ai.Score, ai.Reason)
+		}
+	}
+
+	fmt.Fprintf(&builder, "\n%s\n%s\n", headerStyle.Render("Message"), current.message)
+
+	description := v.PlainDescription()
+	if description == "" {
+		description = strings.TrimSpace(headhunter.StripHTML(v.Snipet.Requirement + "\n" + v.Snipet.Responsibility))
+	}
+	fmt.Fprintf(&builder, "\n%s\n%s\n", headerStyle.Render("Description"), description)
+
+	return builder.String()
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 1 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+type recorder struct {
+	applied  map[string]string
+	excluded map[string]string
+	failIDs  map[string]bool
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		applied:  make(map[string]string),
+		excluded: make(map[string]string),
+		failIDs:  make(map[string]bool),
+	}
+}
+
+func (r *recorder) actions() Actions {
+	return Actions{
+		Apply: func(vacancy *headhunter.Vacancy, message string) error {
+			if r.failIDs[vacancy.ID] {
+				return errors.New("boom")
+			}
+			r.applied[vacancy.ID] = message
+			return nil
+		},
+		Exclude: func(vacancy *headhunter.Vacancy, reason string) error {
+			r.excluded[vacancy.ID] = reason
+			return nil
+		},
+	}
+}
+
+func testVacancies() *headhunter.Vacancies {
+	low := &headhunter.Vacancy{ID: "1", Name: "Low", AI: &headhunter.AIAssessment{Score: 0.3, Message: "ai letter"}}
+	high := &headhunter.Vacancy{ID: "2", Name: "High", AI: &headhunter.AIAssessment{Score: 0.9}}
+	rich := &headhunter.Vacancy{ID: "3", Name: "Rich"}
+	rich.Salary.From = 500000
+
+	return &headhunter.Vacancies{Items: []*headhunter.Vacancy{low, high, rich}}
+}
+
+func press(t *testing.T, m *Model, keys ...string) {
+	t.Helper()
+
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "ctrl+s":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlS}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+
+		_, cmd := m.Update(msg)
+		// Only actions are executed: other commands blink cursors with delays.
+		if cmd == nil || (key != "a" && key != "enter") {
+			continue
+		}
+
+		if done, ok := cmd().(actionDoneMsg); ok {
+			m.Update(done)
+		}
+	}
+}
+
+func ids(m *Model) string {
+	out := make([]string, 0, len(m.items))
+	for _, it := range m.items {
+		out = append(out, it.vacancy.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestModelSorting(t *testing.T) {
+	m := NewModel(testVacancies(), "default", newRecorder().actions())
+
+	press(t, m, "s")
+	if got := ids(m); got != "2,1,3" {
+		t.Fatalf("expected sort by score, got %s", got)
+	}
+
+	press(t, m, "s")
+	if got := ids(m); got != "3,2,1" {
+		t.Fatalf("expected sort by salary, got %s", got)
+	}
+
+	press(t, m, "s")
+	if got := ids(m); got != "1,2,3" {
+		t.Fatalf("expected default order, got %s", got)
+	}
+}
+
+func TestModelMultiSelectApply(t *testing.T) {
+	rec := newRecorder()
+	rec.failIDs["3"] = true
+	m := NewModel(testVacancies(), "default", rec.actions())
+
+	press(t, m, " ", "down", "down", " ", "a")
+
+	if rec.applied["1"] != "ai letter" {
+		t.Fatalf("expected AI message for vacancy 1, got %q", rec.applied["1"])
+	}
+	if _, ok := rec.applied["2"]; ok {
+		t.Fatalf("vacancy 2 was not selected")
+	}
+	if got := ids(m); got != "2,3" {
+		t.Fatalf("expected failed and unselected vacancies to stay, got %s", got)
+	}
+	if !strings.Contains(m.status, "boom") {
+		t.Fatalf("expected failure in status, got %q", m.status)
+	}
+}
+
+func TestModelEditAndExclude(t *testing.T) {
+	rec := newRecorder()
+	m := NewModel(testVacancies(), "default", rec.actions())
+
+	press(t, m, "down", "e", "!", "ctrl+s", "a")
+	if rec.applied["2"] != "default!" {
+		t.Fatalf("expected edited message, got %q", rec.applied["2"])
+	}
+
+	press(t, m, "x", "n", "o", "enter")
+	if rec.excluded["3"] != "no" {
+		t.Fatalf("expected exclusion with reason, got %v", rec.excluded)
+	}
+
+	if got := ids(m); got != "1" {
+		t.Fatalf("unexpected vacancies left: %s", got)
+	}
+}
+
+func TestModelSerializesActions(t *testing.T) {
+	var (
+		running, maxRunning atomic.Int32
+		started             = make(chan struct{}, 2)
+		release             = make(chan struct{})
+	)
+
+	track := func() {
+		if n := running.Add(1); n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		started <- struct{}{}
+		<-release
+		running.Add(-1)
+	}
+
+	m := NewModel(testVacancies(), "default", Actions{
+		Apply:   func(*headhunter.Vacancy, string) error { track(); return nil },
+		Exclude: func(*headhunter.Vacancy, string) error { track(); return nil },
+	})
+
+	// Apply to the first vacancy and exclude the second one while the application is running.
+	_, apply := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	press(t, m, "down", "x")
+	_, exclude := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	done := make(chan tea.Msg, 2)
+	go func() { done <- apply() }()
+	go func() { done <- exclude() }()
+
+	<-started
+	press(t, m, "q")
+	if !m.quitting {
+		t.Fatal("expected to wait for running actions before quitting")
+	}
+
+	close(release)
+
+	var quit bool
+	for range 2 {
+		_, cmd := m.Update(<-done)
+		if cmd != nil {
+			_, quit = cmd().(tea.QuitMsg)
+		}
+	}
+
+	if maxRunning.Load() != 1 {
+		t.Fatalf("actions run concurrently: %d at once", maxRunning.Load())
+	}
+	if !quit {
+		t.Fatal("expected to quit after the actions finished")
+	}
+
+	result := m.Result()
+	if len(result.Applied) != 1 || result.Applied[0] != "1" || len(result.Excluded) != 1 || result.Excluded[0] != "2" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,15 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/spigell/hh-responder/internal/headhunter"
+)
+
+// Run shows the full-screen review of vacancies and blocks until the user quits.
+// It returns vacancies applied to and excluded during the review.
+func Run(vacancies *headhunter.Vacancies, defaultMessage string, actions Actions) (Result, error) {
+	model := NewModel(vacancies, defaultMessage, actions)
+	_, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return model.Result(), err
+}